@@ -0,0 +1,262 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// Supported archive formats for directory assets.
+const (
+	archiveTarGz = "tar.gz"
+	archiveZip   = "zip"
+)
+
+// validArchiveFormats lists the accepted values for an asset's archive option.
+var validArchiveFormats = map[string]bool{archiveTarGz: true, archiveZip: true}
+
+// archiveModTime is the fixed modification time written for every archive entry
+// so that archiving the same directory twice yields identical bytes. It is the
+// earliest timestamp the zip format can represent.
+var archiveModTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// uploadArchiveAsset packs a directory asset into an archive and uploads it to
+// GitLab's generic package registry.
+func (p *GitLabPlugin) uploadArchiveAsset(ctx context.Context, client *gitlab.Client, projectID, packageName, tagName string, asset Asset) (*plugin.Artifact, error) {
+	// Validate and sanitize the asset path to prevent path traversal
+	validatedPath, err := validateAssetPath(asset.Path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asset path %s: %w", asset.Path, err)
+	}
+
+	info, err := os.Lstat(validatedPath)
+	if err != nil {
+		return nil, fmt.Errorf("asset file not accessible %s: %w", asset.Path, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("archive is only supported for directories: %s", asset.Path)
+	}
+
+	archivePath, cleanup, err := archiveDirectory(validatedPath, asset.Archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive %s: %w", asset.Path, err)
+	}
+	defer cleanup()
+
+	return p.publishGenericFile(ctx, client, projectID, packageName, tagName, archivePath)
+}
+
+// archiveDirectory writes the contents of dir to a temporary archive named
+// after the directory. Entries are added in lexical order with normalized
+// permissions and timestamps. Symlinks and other non-regular files are rejected.
+// The returned cleanup function removes the temporary archive.
+func archiveDirectory(dir, format string) (string, func(), error) {
+	if !validArchiveFormats[format] {
+		return "", func() {}, fmt.Errorf("unsupported archive format %q (use tar.gz or zip)", format)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "relicta-gitlab-archive-")
+	if err != nil {
+		return "", func() {}, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	cleanup := func() { _ = os.RemoveAll(tmpDir) }
+
+	archivePath := filepath.Join(tmpDir, filepath.Base(dir)+"."+format)
+	out, err := os.Create(archivePath)
+	if err != nil {
+		cleanup()
+		return "", func() {}, fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	root := filepath.Base(dir)
+	if format == archiveZip {
+		err = writeZipArchive(out, dir, root)
+	} else {
+		err = writeTarGzArchive(out, dir, root)
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		return "", func() {}, err
+	}
+
+	return archivePath, cleanup, nil
+}
+
+// archiveEntry describes a single file or directory to be written to an archive.
+type archiveEntry struct {
+	// name is the slash-separated path inside the archive.
+	name string
+	// path is the absolute path on disk.
+	path string
+	// dir reports whether the entry is a directory.
+	dir bool
+	// mode is the normalized permission bits for the entry.
+	mode fs.FileMode
+}
+
+// collectArchiveEntries walks dir in lexical order and returns the entries to
+// archive, prefixed with root. An empty root places the directory's contents
+// at the top level of the archive.
+func collectArchiveEntries(dir, root string) ([]archiveEntry, error) {
+	var entries []archiveEntry
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if root == "" && rel == "." {
+			return nil
+		}
+		name := filepath.ToSlash(filepath.Join(root, rel))
+
+		switch {
+		case d.Type()&fs.ModeSymlink != 0:
+			return fmt.Errorf("symlinks not allowed in archived directories: %s", name)
+		case d.IsDir():
+			entries = append(entries, archiveEntry{name: name + "/", path: path, dir: true, mode: 0o755})
+		case d.Type().IsRegular():
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			mode := fs.FileMode(0o644)
+			if info.Mode()&0o111 != 0 {
+				mode = 0o755
+			}
+			entries = append(entries, archiveEntry{name: name, path: path, mode: mode})
+		default:
+			return fmt.Errorf("unsupported file type in archived directory: %s", name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// writeTarGzArchive writes dir to w as a gzip-compressed tarball below root.
+func writeTarGzArchive(w io.Writer, dir, root string) error {
+	entries, err := collectArchiveEntries(dir, root)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	for _, entry := range entries {
+		hdr := &tar.Header{
+			Name:    entry.name,
+			Mode:    int64(entry.mode),
+			ModTime: archiveModTime,
+			Format:  tar.FormatPAX,
+		}
+		if entry.dir {
+			hdr.Typeflag = tar.TypeDir
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			continue
+		}
+
+		hdr.Typeflag = tar.TypeReg
+		if err := writeTarFile(tw, hdr, entry.path); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// writeTarFile writes a single regular file to tw.
+func writeTarFile(tw *tar.Writer, hdr *tar.Header, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	hdr.Size = info.Size()
+
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// writeZipArchive writes dir to w as a zip archive below root.
+func writeZipArchive(w io.Writer, dir, root string) error {
+	entries, err := collectArchiveEntries(dir, root)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+
+	for _, entry := range entries {
+		hdr := &zip.FileHeader{
+			Name:     entry.name,
+			Modified: archiveModTime,
+		}
+		hdr.SetMode(entry.mode)
+		if entry.dir {
+			hdr.SetMode(fs.ModeDir | entry.mode)
+			if _, err := zw.CreateHeader(hdr); err != nil {
+				return err
+			}
+			continue
+		}
+
+		hdr.Method = zip.Deflate
+		if err := writeZipFile(zw, hdr, entry.path); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// writeZipFile writes a single regular file to zw.
+func writeZipFile(zw *zip.Writer, hdr *zip.FileHeader, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	fw, err := zw.CreateHeader(hdr)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(fw, f)
+	return err
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// writeTestTree creates a small directory tree used by the archive tests.
+func writeTestTree(t *testing.T, root string) {
+	t.Helper()
+	files := map[string]string{
+		"index.html":        "<html></html>",
+		"css/site.css":      "body {}",
+		"js/app.js":         "console.log(1)",
+		"js/vendor/lib.js":  "var x",
+		"images/.gitkeep":   "",
+		"scripts/build.sh":  "#!/bin/sh",
+		"z-last/readme.txt": "last",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+	if err := os.Chmod(filepath.Join(root, "scripts", "build.sh"), 0700); err != nil {
+		t.Fatalf("failed to chmod: %v", err)
+	}
+}
+
+func TestArchiveDirectoryTarGz(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "site")
+	writeTestTree(t, dir)
+
+	archivePath, cleanup, err := archiveDirectory(dir, "tar.gz")
+	if err != nil {
+		t.Fatalf("archiveDirectory returned error: %v", err)
+	}
+	defer cleanup()
+
+	if filepath.Base(archivePath) != "site.tar.gz" {
+		t.Errorf("expected archive name 'site.tar.gz', got %q", filepath.Base(archivePath))
+	}
+
+	f, err := os.Open(archivePath)
+	if err != nil {
+		t.Fatalf("failed to open archive: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("failed to read gzip: %v", err)
+	}
+	if !gz.ModTime.IsZero() {
+		t.Errorf("expected zero gzip mtime, got %v", gz.ModTime)
+	}
+
+	var names []string
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read tar: %v", err)
+		}
+		names = append(names, hdr.Name)
+		if !hdr.ModTime.Equal(archiveModTime) {
+			t.Errorf("%s: expected mtime %v, got %v", hdr.Name, archiveModTime, hdr.ModTime)
+		}
+		if hdr.Uid != 0 || hdr.Gid != 0 || hdr.Uname != "" || hdr.Gname != "" {
+			t.Errorf("%s: expected no ownership information", hdr.Name)
+		}
+		if hdr.Name == "site/scripts/build.sh" && hdr.Mode != 0o755 {
+			t.Errorf("expected executable mode for build.sh, got %o", hdr.Mode)
+		}
+		if hdr.Name == "site/index.html" && hdr.Mode != 0o644 {
+			t.Errorf("expected 0644 mode for index.html, got %o", hdr.Mode)
+		}
+	}
+
+	expected := []string{
+		"site/",
+		"site/css/",
+		"site/css/site.css",
+		"site/images/",
+		"site/images/.gitkeep",
+		"site/index.html",
+		"site/js/",
+		"site/js/app.js",
+		"site/js/vendor/",
+		"site/js/vendor/lib.js",
+		"site/scripts/",
+		"site/scripts/build.sh",
+		"site/z-last/",
+		"site/z-last/readme.txt",
+	}
+	if len(names) != len(expected) {
+		t.Fatalf("expected %d entries, got %d: %v", len(expected), len(names), names)
+	}
+	for i, name := range expected {
+		if names[i] != name {
+			t.Errorf("entry[%d]: expected %q, got %q", i, name, names[i])
+		}
+	}
+}
+
+func TestArchiveDirectoryZip(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "docs")
+	writeTestTree(t, dir)
+
+	archivePath, cleanup, err := archiveDirectory(dir, "zip")
+	if err != nil {
+		t.Fatalf("archiveDirectory returned error: %v", err)
+	}
+	defer cleanup()
+
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		t.Fatalf("failed to open zip: %v", err)
+	}
+	defer func() { _ = zr.Close() }()
+
+	if len(zr.File) != 14 {
+		t.Fatalf("expected 14 entries, got %d", len(zr.File))
+	}
+	if zr.File[0].Name != "docs/" {
+		t.Errorf("expected first entry 'docs/', got %q", zr.File[0].Name)
+	}
+	for _, f := range zr.File {
+		if !f.Modified.Equal(archiveModTime) {
+			t.Errorf("%s: expected mtime %v, got %v", f.Name, archiveModTime, f.Modified)
+		}
+		if f.Name == "docs/css/site.css" {
+			rc, err := f.Open()
+			if err != nil {
+				t.Fatalf("failed to open entry: %v", err)
+			}
+			content, _ := io.ReadAll(rc)
+			_ = rc.Close()
+			if string(content) != "body {}" {
+				t.Errorf("unexpected content %q", content)
+			}
+		}
+	}
+}
+
+func TestArchiveDirectoryDeterministic(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []string{"tar.gz", "zip"} {
+		t.Run(format, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "bundle")
+			writeTestTree(t, dir)
+
+			first, cleanupFirst, err := archiveDirectory(dir, format)
+			if err != nil {
+				t.Fatalf("archiveDirectory returned error: %v", err)
+			}
+			defer cleanupFirst()
+			firstBytes, _ := os.ReadFile(first)
+
+			// Touch a file so on-disk mtimes differ between runs
+			if err := os.Chtimes(filepath.Join(dir, "index.html"), archiveModTime.AddDate(10, 0, 0), archiveModTime.AddDate(10, 0, 0)); err != nil {
+				t.Fatalf("failed to change mtime: %v", err)
+			}
+
+			second, cleanupSecond, err := archiveDirectory(dir, format)
+			if err != nil {
+				t.Fatalf("archiveDirectory returned error: %v", err)
+			}
+			defer cleanupSecond()
+			secondBytes, _ := os.ReadFile(second)
+
+			if !bytes.Equal(firstBytes, secondBytes) {
+				t.Error("expected identical archives for identical directory contents")
+			}
+		})
+	}
+}
+
+func TestArchiveDirectoryRejectsSymlinks(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "site")
+	writeTestTree(t, dir)
+	if err := os.Symlink("/etc/passwd", filepath.Join(dir, "passwd")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	_, cleanup, err := archiveDirectory(dir, "tar.gz")
+	defer cleanup()
+	if err == nil {
+		t.Fatal("expected error for symlink in directory")
+	}
+	if !contains(err.Error(), "symlinks not allowed") {
+		t.Errorf("expected symlink error, got %q", err.Error())
+	}
+}
+
+func TestArchiveDirectoryUnsupportedFormat(t *testing.T) {
+	t.Parallel()
+
+	_, cleanup, err := archiveDirectory(t.TempDir(), "rar")
+	defer cleanup()
+	if err == nil || !contains(err.Error(), "unsupported archive format") {
+		t.Errorf("expected unsupported format error, got %v", err)
+	}
+}
+
+func TestUploadArchiveAsset(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+
+	tmpDir := t.TempDir()
+	writeTestTree(t, filepath.Join(tmpDir, "public"))
+	if err := os.WriteFile(filepath.Join(tmpDir, "file.txt"), []byte("x"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp directory: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(origDir)
+	})
+
+	var uploadedPath string
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if contains(r.URL.Path, "/packages/generic/") && r.Method == http.MethodPut {
+			uploadedPath = r.URL.Path
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(map[string]any{"message": "201 Created"})
+			return
+		}
+		http.NotFound(w, r)
+	})
+
+	client, err := gitlab.NewClient("glpat-test", gitlab.WithBaseURL(server.URL+"/api/v4/"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	p := &GitLabPlugin{}
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		asset     Asset
+		wantName  string
+		wantError string
+	}{
+		{
+			name:     "directory archived as tar.gz",
+			asset:    Asset{Path: "public", Archive: "tar.gz"},
+			wantName: "public.tar.gz",
+		},
+		{
+			name:     "directory archived as zip",
+			asset:    Asset{Path: "public", Archive: "zip"},
+			wantName: "public.zip",
+		},
+		{
+			name:      "archive requires a directory",
+			asset:     Asset{Path: "file.txt", Archive: "zip"},
+			wantError: "only supported for directories",
+		},
+		{
+			name:      "path traversal blocked",
+			asset:     Asset{Path: "../outside", Archive: "zip"},
+			wantError: "path traversal",
+		},
+		{
+			name:      "directory without archive is rejected",
+			asset:     Asset{Path: "public"},
+			wantError: "directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploadedPath = ""
+			releaseCtx := plugin.ReleaseContext{Version: "1.0.0", TagName: "v1.0.0"}
+			published, err := p.uploadReleaseAsset(ctx, client, &Config{}, "group/project", releaseCtx, tt.asset)
+
+			if tt.wantError != "" {
+				if err == nil {
+					t.Fatalf("expected error containing %q, got nil", tt.wantError)
+				}
+				if !contains(err.Error(), tt.wantError) {
+					t.Errorf("expected error containing %q, got %q", tt.wantError, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(published.artifacts) != 1 || published.artifacts[0].Name != tt.wantName {
+				t.Errorf("expected artifact named %q, got %+v", tt.wantName, published.artifacts)
+			}
+			if !contains(uploadedPath, "/release-assets/v1.0.0/"+tt.wantName) {
+				t.Errorf("unexpected upload path %q", uploadedPath)
+			}
+		})
+	}
+}
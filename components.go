@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// Component is an independently versioned part of a monorepo, released under
+// a path-scoped tag such as sdk/v1.3.0.
+type Component struct {
+	// Name is the component key, e.g. "sdk" or "services/api".
+	Name string `json:"name"`
+	// Version is the component version (default: the release version).
+	Version string `json:"version,omitempty"`
+	// Tag is the tag template (default: "{component}/v{version}").
+	Tag string `json:"tag,omitempty"`
+	// Overrides replaces top-level options (e.g. assets) for this component.
+	Overrides map[string]any `json:"overrides,omitempty"`
+}
+
+const (
+	// defaultComponentTag is the tag template for component releases.
+	defaultComponentTag = "{component}/v{version}"
+	// defaultComponentName is the release name template for component releases.
+	defaultComponentName = "{component} {version}"
+)
+
+// componentResult is the outcome of releasing one component, reported in Outputs.
+type componentResult struct {
+	Component string         `json:"component"`
+	Version   string         `json:"version"`
+	TagName   string         `json:"tag_name"`
+	Success   bool           `json:"success"`
+	Message   string         `json:"message,omitempty"`
+	Error     string         `json:"error,omitempty"`
+	Outputs   map[string]any `json:"outputs,omitempty"`
+}
+
+// componentPackageName derives a generic package name from a component key.
+// Package names cannot contain slashes.
+func componentPackageName(name string) string {
+	return strings.ReplaceAll(name, "/", "-")
+}
+
+// expandComponent replaces {component} in every string of a configuration
+// value, copying maps and slices.
+func expandComponent(v any, name string) any {
+	switch value := v.(type) {
+	case string:
+		return strings.ReplaceAll(value, "{component}", name)
+	case []any:
+		expanded := make([]any, len(value))
+		for i, item := range value {
+			expanded[i] = expandComponent(item, name)
+		}
+		return expanded
+	case map[string]any:
+		expanded := make(map[string]any, len(value))
+		for key, item := range value {
+			expanded[key] = expandComponent(item, name)
+		}
+		return expanded
+	default:
+		return v
+	}
+}
+
+// componentRelease returns the raw configuration and release context for one
+// component. The release name and generic package name default to ones
+// derived from the component key, the prerelease profile is applied for the
+// component version, and {component} is expanded in all options.
+func componentRelease(raw map[string]any, component Component, releaseCtx plugin.ReleaseContext) (map[string]any, plugin.ReleaseContext) {
+	merged := make(map[string]any, len(raw)+len(component.Overrides)+2)
+	for key, value := range raw {
+		if key == "components" {
+			continue
+		}
+		merged[key] = value
+	}
+	for key, value := range component.Overrides {
+		merged[key] = value
+	}
+	if name, _ := merged["name"].(string); name == "" {
+		merged["name"] = defaultComponentName
+	}
+	if packageName, _ := merged["package_name"].(string); packageName == "" {
+		merged["package_name"] = componentPackageName(component.Name)
+	}
+	if sbomDir, _ := merged["sbom_dir"].(string); sbomDir == "" {
+		merged["sbom_dir"] = "{component}"
+	}
+
+	if component.Version != "" {
+		// Versions are bare; the tag template adds the "v"
+		releaseCtx.Version = strings.TrimPrefix(component.Version, "v")
+	}
+	merged = applyPrereleaseProfile(merged, releaseCtx.Version)
+	merged = expandComponent(merged, component.Name).(map[string]any)
+
+	tag := component.Tag
+	if tag == "" {
+		tag = defaultComponentTag
+	}
+	releaseCtx.TagName = strings.ReplaceAll(renderTemplate(tag, releaseCtx), "{component}", component.Name)
+
+	// The release name is rendered here so {version} is the component version
+	if name, ok := merged["name"].(string); ok {
+		merged["name"] = renderTemplate(name, releaseCtx)
+	}
+	return merged, releaseCtx
+}
+
+// publishComponents creates a release for every configured component,
+// publishing each to all targets when targets are set, and aggregates the
+// results according to the partial_failure policy.
+func (p *GitLabPlugin) publishComponents(ctx context.Context, raw map[string]any, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool) (*plugin.ExecuteResponse, error) {
+	policy := cfg.PartialFailure
+	if policy == "" {
+		policy = partialFailureFail
+	}
+
+	var results []componentResult
+	var artifacts []plugin.Artifact
+	var failed []string
+	for _, component := range cfg.Components {
+		componentRaw, componentCtx := componentRelease(raw, component, releaseCtx)
+		componentCfg := p.parseConfig(componentRaw)
+
+		var resp *plugin.ExecuteResponse
+		var err error
+		if len(componentCfg.Targets) > 0 {
+			resp, err = p.publishTargets(ctx, componentRaw, componentCfg, componentCtx, dryRun)
+		} else {
+			resp, err = p.createRelease(ctx, componentCfg, componentCtx, dryRun)
+		}
+
+		result := componentResult{
+			Component: component.Name,
+			Version:   componentCtx.Version,
+			TagName:   componentCtx.TagName,
+		}
+		switch {
+		case err != nil:
+			result.Error = err.Error()
+		case resp.Success:
+			result.Success = true
+			result.Message = resp.Message
+			result.Outputs = resp.Outputs
+			artifacts = append(artifacts, resp.Artifacts...)
+		default:
+			result.Error = resp.Error
+			result.Outputs = resp.Outputs
+		}
+		results = append(results, result)
+
+		if !result.Success {
+			failed = append(failed, fmt.Sprintf("%s: %s", result.Component, result.Error))
+			if policy == partialFailureFailFast {
+				break
+			}
+		}
+	}
+
+	succeeded := len(results) - len(failed)
+	resp := &plugin.ExecuteResponse{
+		Success:   len(failed) == 0 || (policy == partialFailureAllow && succeeded > 0),
+		Message:   fmt.Sprintf("Released %d of %d components", succeeded, len(cfg.Components)),
+		Artifacts: artifacts,
+		Outputs: map[string]any{
+			"components": results,
+		},
+	}
+	if dryRun {
+		resp.Message = fmt.Sprintf("Would release %d components", len(cfg.Components))
+	}
+	if len(failed) > 0 {
+		resp.Error = fmt.Sprintf("%d of %d components failed: %s", len(failed), len(cfg.Components), strings.Join(failed, "; "))
+	}
+	return resp, nil
+}
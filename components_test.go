@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestComponentRelease(t *testing.T) {
+	raw := map[string]any{
+		"project_id": "acme/monorepo",
+		"assets":     []any{"{component}/dist/*.tar.gz", map[string]any{"path": "{component}/docs", "archive": "zip"}},
+		"components": []any{},
+	}
+	releaseCtx := plugin.ReleaseContext{Version: "2.0.0", TagName: "v2.0.0"}
+
+	tests := []struct {
+		name        string
+		component   Component
+		wantTag     string
+		wantVersion string
+		wantName    string
+		wantPackage string
+		wantAsset   string
+	}{
+		{
+			name:        "derived from the key",
+			component:   Component{Name: "sdk", Version: "1.3.0"},
+			wantTag:     "sdk/v1.3.0",
+			wantVersion: "1.3.0",
+			wantName:    "sdk 1.3.0",
+			wantPackage: "sdk",
+			wantAsset:   "sdk/dist/*.tar.gz",
+		},
+		{
+			name:        "version with a v prefix",
+			component:   Component{Name: "sdk", Version: "v1.3.0"},
+			wantTag:     "sdk/v1.3.0",
+			wantVersion: "1.3.0",
+			wantName:    "sdk 1.3.0",
+			wantPackage: "sdk",
+			wantAsset:   "sdk/dist/*.tar.gz",
+		},
+		{
+			name:        "nested key and release version",
+			component:   Component{Name: "services/api"},
+			wantTag:     "services/api/v2.0.0",
+			wantVersion: "2.0.0",
+			wantName:    "services/api 2.0.0",
+			wantPackage: "services-api",
+			wantAsset:   "services/api/dist/*.tar.gz",
+		},
+		{
+			name:        "custom tag and overrides",
+			component:   Component{Name: "cli", Version: "0.9.1", Tag: "{component}-{version}", Overrides: map[string]any{"name": "CLI {version}", "assets": []any{"bin/{component}"}}},
+			wantTag:     "cli-0.9.1",
+			wantVersion: "0.9.1",
+			wantName:    "CLI 0.9.1",
+			wantPackage: "cli",
+			wantAsset:   "bin/cli",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			componentRaw, componentCtx := componentRelease(raw, tt.component, releaseCtx)
+			if componentCtx.TagName != tt.wantTag || componentCtx.Version != tt.wantVersion {
+				t.Errorf("got tag %q version %q, want %q %q", componentCtx.TagName, componentCtx.Version, tt.wantTag, tt.wantVersion)
+			}
+
+			cfg := (&GitLabPlugin{}).parseConfig(componentRaw)
+			if cfg.Name != tt.wantName || cfg.PackageName != tt.wantPackage {
+				t.Errorf("got name %q package %q, want %q %q", cfg.Name, cfg.PackageName, tt.wantName, tt.wantPackage)
+			}
+			if len(cfg.Assets) == 0 || cfg.Assets[0].Path != tt.wantAsset {
+				t.Errorf("expected first asset %q, got %+v", tt.wantAsset, cfg.Assets)
+			}
+			if cfg.SBOMDir != tt.component.Name {
+				t.Errorf("expected sbom_dir %q, got %q", tt.component.Name, cfg.SBOMDir)
+			}
+			if len(cfg.Components) != 0 {
+				t.Errorf("component config must not contain components")
+			}
+		})
+	}
+
+	// The shared configuration is not modified
+	if raw["assets"].([]any)[0] != "{component}/dist/*.tar.gz" {
+		t.Errorf("raw config was modified: %v", raw["assets"])
+	}
+}
+
+func TestExecutePublishComponents(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+	tmpDir := t.TempDir()
+	for _, path := range []string{"sdk/dist/sdk.tar.gz", "cli/dist/cli.tar.gz"} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, filepath.Dir(path)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, path), []byte(path), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	chdirForTest(t, tmpDir)
+
+	var mu sync.Mutex
+	var releases []map[string]any
+	var uploads []string
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodPut && contains(r.URL.Path, "/packages/generic/"):
+			uploads = append(uploads, r.URL.EscapedPath())
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		case r.Method == http.MethodPost && contains(r.URL.Path, "/assets/links"):
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 1}`))
+		case r.Method == http.MethodPost && contains(r.URL.Path, "/releases"):
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			releases = append(releases, body)
+			if body["tag_name"] == "cli/v0.9.1" {
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`{"message": "Release already exists"}`))
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(body)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	p := &GitLabPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"token":      "glpat-test",
+			"base_url":   server.URL,
+			"project_id": "acme/monorepo",
+			"assets":     []any{"{component}/dist/*.tar.gz"},
+			"components": []any{
+				map[string]any{"name": "sdk", "version": "1.3.0"},
+				map[string]any{"name": "cli", "version": "0.9.1"},
+			},
+		},
+		Context: plugin.ReleaseContext{Version: "2.0.0", TagName: "v2.0.0"},
+	})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+
+	// The cli release fails, but the sdk release is still created
+	if resp.Success || resp.Message != "Released 1 of 2 components" || !contains(resp.Error, "cli:") {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	if len(releases) != 2 || releases[0]["tag_name"] != "sdk/v1.3.0" || releases[0]["name"] != "sdk 1.3.0" {
+		t.Fatalf("unexpected releases %v", releases)
+	}
+	if len(uploads) != 1 || uploads[0] != "/api/v4/projects/acme%2Fmonorepo/packages/generic/sdk/sdk-v1%2E3%2E0/sdk%2Etar%2Egz" {
+		t.Errorf("unexpected uploads %v", uploads)
+	}
+
+	results := resp.Outputs["components"].([]componentResult)
+	if !results[0].Success || results[0].TagName != "sdk/v1.3.0" || results[1].Success {
+		t.Errorf("unexpected results %+v", results)
+	}
+}
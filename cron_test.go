@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr bool
+	}{
+		{name: "every minute", expr: "* * * * *"},
+		{name: "lists ranges and steps", expr: "0,30 9-17 */2 1-6 1-5"},
+		{name: "names", expr: "0 23 * dec fri"},
+		{name: "sunday as seven", expr: "0 0 * * 7"},
+		{name: "too few fields", expr: "0 23 * *", wantErr: true},
+		{name: "out of range", expr: "60 * * * *", wantErr: true},
+		{name: "reversed range", expr: "0 17-9 * * *", wantErr: true},
+		{name: "bad step", expr: "*/0 * * * *", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCron(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseCron(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCronPrevNext(t *testing.T) {
+	// Friday 2025-03-07 15:04 UTC
+	now := time.Date(2025, 3, 7, 15, 4, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr     string
+		wantPrev time.Time
+		wantNext time.Time
+	}{
+		{
+			expr:     "0 23 * * 5",
+			wantPrev: time.Date(2025, 2, 28, 23, 0, 0, 0, time.UTC),
+			wantNext: time.Date(2025, 3, 7, 23, 0, 0, 0, time.UTC),
+		},
+		{
+			expr:     "*/15 * * * *",
+			wantPrev: time.Date(2025, 3, 7, 15, 0, 0, 0, time.UTC),
+			wantNext: time.Date(2025, 3, 7, 15, 15, 0, 0, time.UTC),
+		},
+		{
+			expr:     "0 0 1 jan *",
+			wantPrev: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantNext: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			// Day of month or day of week when both are restricted
+			expr:     "0 12 1 * mon",
+			wantPrev: time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC),
+			wantNext: time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := parseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("parseCron: %v", err)
+			}
+			if got, ok := s.prev(now); !ok || !got.Equal(tt.wantPrev) {
+				t.Errorf("prev = %v (%v), want %v", got, ok, tt.wantPrev)
+			}
+			if got, ok := s.next(now); !ok || !got.Equal(tt.wantNext) {
+				t.Errorf("next = %v (%v), want %v", got, ok, tt.wantNext)
+			}
+		})
+	}
+
+	// February 30th never occurs
+	s, _ := parseCron("0 0 30 2 *")
+	if _, ok := s.prev(now); ok {
+		t.Error("expected no previous occurrence for an impossible date")
+	}
+}
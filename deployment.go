@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// ensureEnvironment returns the named environment, creating it or updating
+// its external URL as needed.
+func ensureEnvironment(ctx context.Context, client *gitlab.Client, projectID, name, externalURL string) (*gitlab.Environment, error) {
+	envs, _, err := client.Environments.ListEnvironments(projectID, &gitlab.ListEnvironmentsOptions{Name: gitlab.Ptr(name)}, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up environment %s: %w", name, err)
+	}
+
+	for _, env := range envs {
+		if env.Name != name {
+			continue
+		}
+		if externalURL == "" || env.ExternalURL == externalURL {
+			return env, nil
+		}
+		env, _, err = client.Environments.EditEnvironment(projectID, env.ID, &gitlab.EditEnvironmentOptions{
+			ExternalURL: gitlab.Ptr(externalURL),
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to update environment %s: %w", name, err)
+		}
+		return env, nil
+	}
+
+	opts := &gitlab.CreateEnvironmentOptions{Name: gitlab.Ptr(name)}
+	if externalURL != "" {
+		opts.ExternalURL = gitlab.Ptr(externalURL)
+	}
+	env, _, err := client.Environments.CreateEnvironment(projectID, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create environment %s: %w", name, err)
+	}
+	return env, nil
+}
+
+// recordDeployment creates a deployment of the release in the configured
+// environment with the given status.
+func (p *GitLabPlugin) recordDeployment(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, status gitlab.DeploymentStatusValue, dryRun bool) (*plugin.ExecuteResponse, error) {
+	client, err := p.getClient(cfg)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to create GitLab client: %v", err),
+		}, nil
+	}
+
+	projectID := resolveProjectID(cfg, releaseCtx)
+	if projectID == "" {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   "project_id is required (set in config or provide repository owner/name)",
+		}, nil
+	}
+
+	if releaseCtx.CommitSHA == "" {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   "recording a deployment requires the release commit SHA",
+		}, nil
+	}
+
+	// A failed release may not have created its tag; fall back to the branch
+	ref, isTag := releaseCtx.TagName, true
+	if status == gitlab.DeploymentStatusFailed || ref == "" {
+		if releaseCtx.Branch != "" {
+			ref, isTag = releaseCtx.Branch, false
+		}
+	}
+
+	if dryRun {
+		return &plugin.ExecuteResponse{
+			Success: true,
+			Message: fmt.Sprintf("Would record %s deployment of %s to %s", status, ref, cfg.Environment),
+		}, nil
+	}
+
+	env, err := ensureEnvironment(ctx, client, projectID, cfg.Environment, cfg.EnvironmentURL)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	deployment, _, err := client.Deployments.CreateProjectDeployment(projectID, &gitlab.CreateProjectDeploymentOptions{
+		Environment: gitlab.Ptr(env.Name),
+		Ref:         gitlab.Ptr(ref),
+		SHA:         gitlab.Ptr(releaseCtx.CommitSHA),
+		Tag:         gitlab.Ptr(isTag),
+		Status:      gitlab.Ptr(status),
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to create deployment: %v", err),
+		}, nil
+	}
+
+	return &plugin.ExecuteResponse{
+		Success: true,
+		Message: fmt.Sprintf("Recorded %s deployment of %s to %s", status, ref, env.Name),
+		Outputs: map[string]any{
+			"deployment_id":  deployment.ID,
+			"environment_id": env.ID,
+			"environment":    env.Name,
+		},
+	}, nil
+}
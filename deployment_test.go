@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestRecordDeployment(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{Version: "1.4.0", TagName: "v1.4.0", Branch: "main", CommitSHA: "abc123"}
+
+	tests := []struct {
+		name       string
+		hook       plugin.Hook
+		existing   []gitlab.Environment
+		config     map[string]any
+		wantCalls  []string
+		wantStatus string
+		wantRef    string
+		wantTag    bool
+	}{
+		{
+			name:       "success creates environment",
+			hook:       plugin.HookOnSuccess,
+			config:     map[string]any{"environment": "production", "environment_url": "https://app.example.com"},
+			wantCalls:  []string{"GET environments", "POST environments", "POST deployments"},
+			wantStatus: "success",
+			wantRef:    "v1.4.0",
+			wantTag:    true,
+		},
+		{
+			name:       "error reuses environment",
+			hook:       plugin.HookOnError,
+			existing:   []gitlab.Environment{{ID: 9, Name: "production"}},
+			config:     map[string]any{"environment": "production"},
+			wantCalls:  []string{"GET environments", "POST deployments"},
+			wantStatus: "failed",
+			wantRef:    "main",
+		},
+		{
+			name:       "updates changed external URL",
+			hook:       plugin.HookOnSuccess,
+			existing:   []gitlab.Environment{{ID: 9, Name: "production", ExternalURL: "https://old.example.com"}},
+			config:     map[string]any{"environment": "production", "environment_url": "https://app.example.com"},
+			wantCalls:  []string{"GET environments", "PUT environments/9", "POST deployments"},
+			wantStatus: "success",
+			wantRef:    "v1.4.0",
+			wantTag:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var calls []string
+			var deployment map[string]any
+
+			server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				const prefix = "/api/v4/projects/group/project/"
+				calls = append(calls, r.Method+" "+r.URL.Path[len(prefix):])
+
+				switch r.URL.Path[len(prefix):] {
+				case "environments":
+					if r.Method == http.MethodGet {
+						_ = json.NewEncoder(w).Encode(tt.existing)
+						return
+					}
+					var body map[string]any
+					_ = json.NewDecoder(r.Body).Decode(&body)
+					w.WriteHeader(http.StatusCreated)
+					_ = json.NewEncoder(w).Encode(gitlab.Environment{ID: 10, Name: body["name"].(string)})
+				case "environments/9":
+					_ = json.NewEncoder(w).Encode(gitlab.Environment{ID: 9, Name: "production"})
+				case "deployments":
+					_ = json.NewDecoder(r.Body).Decode(&deployment)
+					w.WriteHeader(http.StatusCreated)
+					_ = json.NewEncoder(w).Encode(gitlab.Deployment{ID: 77})
+				default:
+					http.NotFound(w, r)
+				}
+			})
+
+			config := map[string]any{"token": "glpat-test", "base_url": server.URL, "project_id": "group/project"}
+			for k, v := range tt.config {
+				config[k] = v
+			}
+
+			p := &GitLabPlugin{}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{Hook: tt.hook, Config: config, Context: releaseCtx})
+			if err != nil {
+				t.Fatalf("Execute returned error: %v", err)
+			}
+			if !resp.Success {
+				t.Fatalf("expected success, got error: %s", resp.Error)
+			}
+			if resp.Outputs["deployment_id"] != int64(77) {
+				t.Errorf("expected deployment_id output, got %v", resp.Outputs)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(calls) != len(tt.wantCalls) {
+				t.Fatalf("expected calls %v, got %v", tt.wantCalls, calls)
+			}
+			for i := range calls {
+				if calls[i] != tt.wantCalls[i] {
+					t.Errorf("call %d: expected %q, got %q", i, tt.wantCalls[i], calls[i])
+				}
+			}
+			if deployment["status"] != tt.wantStatus || deployment["ref"] != tt.wantRef || deployment["tag"] != tt.wantTag || deployment["sha"] != "abc123" {
+				t.Errorf("unexpected deployment %v", deployment)
+			}
+		})
+	}
+}
+
+func TestRecordDeploymentRequiresCommit(t *testing.T) {
+	p := &GitLabPlugin{}
+	cfg := &Config{Token: "glpat-test", ProjectID: "group/project", Environment: "production"}
+
+	resp, err := p.recordDeployment(context.Background(), cfg, plugin.ReleaseContext{TagName: "v1.0.0"}, gitlab.DeploymentStatusSuccess, false)
+	if err != nil {
+		t.Fatalf("recordDeployment returned error: %v", err)
+	}
+	if resp.Success || !contains(resp.Error, "commit SHA") {
+		t.Errorf("expected commit SHA error, got %+v", resp)
+	}
+
+	resp, _ = p.recordDeployment(context.Background(), cfg, plugin.ReleaseContext{TagName: "v1.0.0", CommitSHA: "abc"}, gitlab.DeploymentStatusSuccess, true)
+	if !resp.Success || !contains(resp.Message, "Would record success deployment of v1.0.0 to production") {
+		t.Errorf("unexpected dry-run response %+v", resp)
+	}
+}
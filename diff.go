@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// fieldChange is a release field whose planned value differs from the
+// published one.
+type fieldChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// linkChange is a link whose URL, type or filepath would change.
+type linkChange struct {
+	Name string      `json:"name"`
+	From releaseLink `json:"from"`
+	To   AssetLink   `json:"to"`
+}
+
+// releaseDiff describes how a planned release differs from the published
+// release with the same tag. Links are matched by name; links that are not
+// planned are only listed as removed when they would be pruned.
+type releaseDiff struct {
+	Name              *fieldChange  `json:"name,omitempty"`
+	Description       *fieldChange  `json:"description,omitempty"`
+	MilestonesAdded   []string      `json:"milestones_added"`
+	MilestonesRemoved []string      `json:"milestones_removed"`
+	LinksAdded        []AssetLink   `json:"links_added"`
+	LinksRemoved      []releaseLink `json:"links_removed"`
+	LinksChanged      []linkChange  `json:"links_changed"`
+	// AssetsToUpload lists the files publishing would upload.
+	AssetsToUpload []plannedAsset `json:"assets_to_upload"`
+}
+
+// empty reports whether the planned release matches the published one.
+func (d *releaseDiff) empty() bool {
+	return d.Name == nil && d.Description == nil &&
+		len(d.MilestonesAdded) == 0 && len(d.MilestonesRemoved) == 0 &&
+		len(d.LinksAdded) == 0 && len(d.LinksRemoved) == 0 && len(d.LinksChanged) == 0
+}
+
+// summary lists the changed parts of the release, e.g. "links (+1 -0 ~2)".
+func (d *releaseDiff) summary() []string {
+	var parts []string
+	if d.Name != nil {
+		parts = append(parts, "name")
+	}
+	if d.Description != nil {
+		parts = append(parts, "description")
+	}
+	if len(d.MilestonesAdded) > 0 || len(d.MilestonesRemoved) > 0 {
+		parts = append(parts, fmt.Sprintf("milestones (+%d -%d)", len(d.MilestonesAdded), len(d.MilestonesRemoved)))
+	}
+	if len(d.LinksAdded) > 0 || len(d.LinksRemoved) > 0 || len(d.LinksChanged) > 0 {
+		parts = append(parts, fmt.Sprintf("links (+%d -%d ~%d)", len(d.LinksAdded), len(d.LinksRemoved), len(d.LinksChanged)))
+	}
+	if len(d.AssetsToUpload) > 0 {
+		parts = append(parts, fmt.Sprintf("%d assets to upload", len(d.AssetsToUpload)))
+	}
+	return parts
+}
+
+// plannedLinks returns the links of a create release request.
+func plannedLinks(opts *gitlab.CreateReleaseOptions) []AssetLink {
+	if opts.Assets == nil {
+		return nil
+	}
+	links := make([]AssetLink, 0, len(opts.Assets.Links))
+	for _, link := range opts.Assets.Links {
+		planned := AssetLink{
+			Name:     *link.Name,
+			URL:      *link.URL,
+			LinkType: string(gitlab.OtherLinkType),
+		}
+		if link.LinkType != nil {
+			planned.LinkType = string(*link.LinkType)
+		}
+		if link.DirectAssetPath != nil {
+			planned.FilePath = *link.DirectAssetPath
+		}
+		links = append(links, planned)
+	}
+	return links
+}
+
+// linkFilePath returns the filepath of a published link, which GitLab only
+// reports as part of its direct asset URL.
+func linkFilePath(link releaseLink) string {
+	if link.DirectAssetURL == "" || link.DirectAssetURL == link.URL {
+		return ""
+	}
+	if i := strings.Index(link.DirectAssetURL, "/downloads/"); i >= 0 {
+		return link.DirectAssetURL[i+len("/downloads"):]
+	}
+	return ""
+}
+
+// diffRelease compares a published release with a create release request.
+// Published links that are not in the request are only removed when prune
+// is set and returns true for them.
+func diffRelease(existing *gitlab.Release, opts *gitlab.CreateReleaseOptions, prune func(releaseLink) bool) *releaseDiff {
+	diff := &releaseDiff{
+		MilestonesAdded:   []string{},
+		MilestonesRemoved: []string{},
+		LinksAdded:        []AssetLink{},
+		LinksRemoved:      []releaseLink{},
+		LinksChanged:      []linkChange{},
+		AssetsToUpload:    []plannedAsset{},
+	}
+
+	if opts.Name != nil && *opts.Name != existing.Name {
+		diff.Name = &fieldChange{From: existing.Name, To: *opts.Name}
+	}
+	if opts.Description != nil && *opts.Description != existing.Description {
+		diff.Description = &fieldChange{From: existing.Description, To: *opts.Description}
+	}
+
+	var planned []string
+	if opts.Milestones != nil {
+		planned = *opts.Milestones
+	}
+	published := map[string]bool{}
+	for _, milestone := range existing.Milestones {
+		if milestone != nil {
+			published[milestone.Title] = true
+		}
+	}
+	wanted := map[string]bool{}
+	for _, title := range planned {
+		wanted[title] = true
+		if !published[title] {
+			diff.MilestonesAdded = append(diff.MilestonesAdded, title)
+		}
+	}
+	for _, milestone := range existing.Milestones {
+		if milestone != nil && !wanted[milestone.Title] {
+			diff.MilestonesRemoved = append(diff.MilestonesRemoved, milestone.Title)
+		}
+	}
+
+	current := map[string]releaseLink{}
+	for _, link := range existing.Assets.Links {
+		if link != nil {
+			current[link.Name] = releaseLink{
+				ID:             link.ID,
+				Name:           link.Name,
+				URL:            link.URL,
+				DirectAssetURL: link.DirectAssetURL,
+				LinkType:       string(link.LinkType),
+			}
+		}
+	}
+	wantedLinks := map[string]bool{}
+	for _, link := range plannedLinks(opts) {
+		wantedLinks[link.Name] = true
+		have, ok := current[link.Name]
+		switch {
+		case !ok:
+			diff.LinksAdded = append(diff.LinksAdded, link)
+		case have.URL != link.URL || have.LinkType != link.LinkType || linkFilePath(have) != link.FilePath:
+			diff.LinksChanged = append(diff.LinksChanged, linkChange{Name: link.Name, From: have, To: link})
+		}
+	}
+	for _, link := range existing.Assets.Links {
+		if prune != nil && link != nil && !wantedLinks[link.Name] && prune(current[link.Name]) {
+			diff.LinksRemoved = append(diff.LinksRemoved, current[link.Name])
+		}
+	}
+	return diff
+}
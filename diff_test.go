@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// mustReleaseOptions builds the release request for v1.0.0.
+func mustReleaseOptions(t *testing.T, cfg *Config) *gitlab.CreateReleaseOptions {
+	t.Helper()
+	opts, err := releaseOptions(cfg, plugin.ReleaseContext{Version: "1.0.0", TagName: "v1.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return opts
+}
+
+// pruneAll prunes every link that is not in the request.
+func pruneAll(releaseLink) bool { return true }
+
+func TestDiffRelease(t *testing.T) {
+	t.Parallel()
+
+	existing := &gitlab.Release{
+		TagName:     "v1.0.0",
+		Name:        "Release 1.0.0",
+		Description: "Notes",
+		Milestones:  []*gitlab.ReleaseMilestone{{Title: "1.0"}, {Title: "Q3"}},
+	}
+	existing.Assets.Links = []*gitlab.ReleaseLink{
+		{ID: 1, Name: "Docs", URL: "https://docs.example.com", DirectAssetURL: "https://docs.example.com", LinkType: gitlab.RunbookLinkType},
+		{ID: 2, Name: "Binary", URL: "https://cdn.example.com/app", DirectAssetURL: "https://gitlab.example.com/acme/app/-/releases/v1.0.0/downloads/bin/app", LinkType: gitlab.PackageLinkType},
+		{ID: 3, Name: "Old", URL: "https://old.example.com", LinkType: gitlab.OtherLinkType},
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		cfg := &Config{
+			Description: "Notes",
+			Milestones:  []string{"Q3", "1.0"},
+			AssetLinks: []AssetLink{
+				{Name: "Docs", URL: "https://docs.example.com", LinkType: "runbook"},
+				{Name: "Binary", URL: "https://cdn.example.com/app", FilePath: "/bin/app", LinkType: "package"},
+				{Name: "Old", URL: "https://old.example.com"},
+			},
+		}
+		diff := diffRelease(existing, mustReleaseOptions(t, cfg), pruneAll)
+		if !diff.empty() {
+			t.Errorf("expected no changes, got %+v", diff)
+		}
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		cfg := &Config{
+			Name:        "Release {version} (LTS)",
+			Description: "Notes",
+			Milestones:  []string{"1.0", "1.0-hotfix"},
+			AssetLinks: []AssetLink{
+				{Name: "Docs", URL: "https://docs.example.com/v1", LinkType: "runbook"},
+				{Name: "Binary", URL: "https://cdn.example.com/app", FilePath: "/bin/app-linux", LinkType: "package"},
+				{Name: "Checksums", URL: "https://cdn.example.com/sha256sums"},
+			},
+		}
+		diff := diffRelease(existing, mustReleaseOptions(t, cfg), pruneAll)
+
+		if diff.Name == nil || diff.Name.From != "Release 1.0.0" || diff.Name.To != "Release 1.0.0 (LTS)" {
+			t.Errorf("unexpected name change %+v", diff.Name)
+		}
+		if diff.Description != nil {
+			t.Errorf("unexpected description change %+v", diff.Description)
+		}
+		if len(diff.MilestonesAdded) != 1 || diff.MilestonesAdded[0] != "1.0-hotfix" || len(diff.MilestonesRemoved) != 1 || diff.MilestonesRemoved[0] != "Q3" {
+			t.Errorf("unexpected milestone changes +%v -%v", diff.MilestonesAdded, diff.MilestonesRemoved)
+		}
+		if len(diff.LinksAdded) != 1 || diff.LinksAdded[0].Name != "Checksums" || diff.LinksAdded[0].LinkType != "other" {
+			t.Errorf("unexpected added links %+v", diff.LinksAdded)
+		}
+		if len(diff.LinksRemoved) != 1 || diff.LinksRemoved[0].ID != 3 {
+			t.Errorf("unexpected removed links %+v", diff.LinksRemoved)
+		}
+		if len(diff.LinksChanged) != 2 || diff.LinksChanged[0].From.ID != 1 || diff.LinksChanged[1].To.FilePath != "/bin/app-linux" {
+			t.Errorf("unexpected changed links %+v", diff.LinksChanged)
+		}
+	})
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// Asset registry values for Linux distribution packages.
+const (
+	registryDebian = "debian"
+	registryRPM    = "rpm"
+)
+
+// defaultDebianComponent is the component used when none is configured.
+const defaultDebianComponent = "main"
+
+// parseDebianFileName splits a name_version_arch.deb file name.
+func parseDebianFileName(fileName string) (name, version, arch string, err error) {
+	if !strings.HasSuffix(fileName, ".deb") {
+		return "", "", "", fmt.Errorf("unsupported debian package: %s (expected .deb)", fileName)
+	}
+	parts := strings.Split(strings.TrimSuffix(fileName, ".deb"), "_")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("invalid debian package file name: %s (expected name_version_arch.deb)", fileName)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// debianPoolLetter returns the pool directory for a source package name,
+// following the Debian archive layout ("libf" for libfoo, "f" for foo).
+func debianPoolLetter(name string) string {
+	if strings.HasPrefix(name, "lib") && len(name) > 3 {
+		return name[:4]
+	}
+	return name[:1]
+}
+
+// parseRPMFileName splits a name-version-release.arch.rpm file name.
+func parseRPMFileName(fileName string) (name, version, release, arch string, err error) {
+	if !strings.HasSuffix(fileName, ".rpm") {
+		return "", "", "", "", fmt.Errorf("unsupported rpm package: %s (expected .rpm)", fileName)
+	}
+	base := strings.TrimSuffix(fileName, ".rpm")
+
+	dot := strings.LastIndex(base, ".")
+	if dot <= 0 {
+		return "", "", "", "", fmt.Errorf("invalid rpm file name: %s (expected name-version-release.arch.rpm)", fileName)
+	}
+	base, arch = base[:dot], base[dot+1:]
+
+	parts := strings.Split(base, "-")
+	if len(parts) < 3 || arch == "" {
+		return "", "", "", "", fmt.Errorf("invalid rpm file name: %s (expected name-version-release.arch.rpm)", fileName)
+	}
+	n := len(parts)
+	return strings.Join(parts[:n-2], "-"), parts[n-2], parts[n-1], arch, nil
+}
+
+// publishDebianPackage uploads a .deb to the project's Debian registry for the
+// configured distribution and component.
+func (p *GitLabPlugin) publishDebianPackage(ctx context.Context, client *gitlab.Client, projectID string, asset Asset) (*publishedAsset, error) {
+	debPath, err := validateAssetFile(asset.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := filepath.Base(debPath)
+	name, version, _, err := parseDebianFileName(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if asset.Distribution == "" {
+		return nil, fmt.Errorf("debian package requires a distribution: %s", asset.Path)
+	}
+	component := asset.Component
+	if component == "" {
+		component = defaultDebianComponent
+	}
+
+	project := gitlab.PathEscape(projectID)
+	query := url.Values{}
+	query.Set("distribution", asset.Distribution)
+	query.Set("component", component)
+
+	apiPath := fmt.Sprintf("projects/%s/packages/debian/%s", project, gitlab.PathEscape(fileName))
+	artifact, err := putPackageFile(ctx, client, apiPath, query, debPath, fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish debian package %s: %w", fileName, err)
+	}
+	artifact.Type = "debian_package"
+
+	poolURL := fmt.Sprintf("%sprojects/%s/packages/debian/pool/%s/%s/%s/%s/%s",
+		client.BaseURL().String(), project, gitlab.PathEscape(asset.Distribution), gitlab.PathEscape(debianPoolLetter(name)),
+		gitlab.PathEscape(name), gitlab.PathEscape(version), gitlab.PathEscape(fileName))
+	artifact.Path = poolURL
+
+	return &publishedAsset{
+		artifacts: []plugin.Artifact{*artifact},
+		link: &AssetLink{
+			Name:     fmt.Sprintf("%s %s (Debian %s/%s)", name, version, asset.Distribution, component),
+			URL:      poolURL,
+			LinkType: "package",
+		},
+	}, nil
+}
+
+// publishRPMPackage uploads an .rpm to the project's RPM registry.
+func (p *GitLabPlugin) publishRPMPackage(ctx context.Context, client *gitlab.Client, projectID string, asset Asset) (*publishedAsset, error) {
+	rpmPath, err := validateAssetFile(asset.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := filepath.Base(rpmPath)
+	name, version, release, _, err := parseRPMFileName(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(rpmPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rpm package: %w", err)
+	}
+
+	uploadPath := fmt.Sprintf("projects/%s/packages/rpm", gitlab.PathEscape(projectID))
+	req, err := client.UploadRequest(http.MethodPost, uploadPath, bytes.NewReader(data), fileName, gitlab.UploadType("file"), nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := client.Do(req, nil); err != nil {
+		return nil, fmt.Errorf("failed to publish rpm package %s: %w", fileName, err)
+	}
+
+	// RPM files are downloaded by package file ID, which the upload does not
+	// return, so look the file up by name and checksum.
+	checksum := sha256Digest(data)
+	fileID, err := rpmPackageFileID(ctx, client, projectID, fileName, strings.TrimPrefix(checksum, "sha256:"))
+	if err != nil {
+		return nil, err
+	}
+	downloadURL := fmt.Sprintf("%sprojects/%s/packages/rpm/%d/%s",
+		client.BaseURL().String(), gitlab.PathEscape(projectID), fileID, gitlab.PathEscape(fileName))
+
+	return &publishedAsset{
+		artifacts: []plugin.Artifact{{
+			Name:     fileName,
+			Path:     downloadURL,
+			Type:     "rpm_package",
+			Size:     int64(len(data)),
+			Checksum: checksum,
+		}},
+		link: &AssetLink{
+			Name:     fmt.Sprintf("%s %s-%s (RPM)", name, version, release),
+			URL:      downloadURL,
+			LinkType: "package",
+		},
+	}, nil
+}
+
+// rpmPackageFileID returns the ID of an uploaded RPM file. GitLab extracts
+// the package name from the file after the upload, so the most recently
+// created RPM packages are searched for a file with the same name and sha256.
+func rpmPackageFileID(ctx context.Context, client *gitlab.Client, projectID, fileName, sha256 string) (int64, error) {
+	packages, _, err := client.Packages.ListProjectPackages(projectID, &gitlab.ListProjectPackagesOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 20},
+		OrderBy:     gitlab.Ptr("created_at"),
+		Sort:        gitlab.Ptr("desc"),
+		PackageType: gitlab.Ptr(registryRPM),
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list rpm packages: %w", err)
+	}
+	for _, pkg := range packages {
+		files, _, err := client.Packages.ListPackageFiles(projectID, pkg.ID, &gitlab.ListPackageFilesOptions{
+			ListOptions: gitlab.ListOptions{PerPage: 100},
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return 0, fmt.Errorf("failed to list files of rpm package %s: %w", pkg.Name, err)
+		}
+		for _, file := range files {
+			if file.FileName == fileName && file.FileSHA256 == sha256 {
+				return file.ID, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("uploaded rpm package %s is not listed in the package registry", fileName)
+}
+
+// webBaseURL returns the GitLab web URL for the client's API base URL.
+func webBaseURL(client *gitlab.Client) string {
+	return strings.TrimSuffix(strings.TrimSuffix(client.BaseURL().String(), "/"), "/api/v4")
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestParseDebianFileName(t *testing.T) {
+	tests := []struct {
+		fileName    string
+		wantName    string
+		wantVersion string
+		wantArch    string
+		wantErr     bool
+	}{
+		{fileName: "acme-cli_1.4.0-1_amd64.deb", wantName: "acme-cli", wantVersion: "1.4.0-1", wantArch: "amd64"},
+		{fileName: "libacme_2.0.0_all.deb", wantName: "libacme", wantVersion: "2.0.0", wantArch: "all"},
+		{fileName: "acme-cli-1.4.0.deb", wantErr: true},
+		{fileName: "acme-cli_1.4.0_amd64.rpm", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fileName, func(t *testing.T) {
+			name, version, arch, err := parseDebianFileName(tt.fileName)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if name != tt.wantName || version != tt.wantVersion || arch != tt.wantArch {
+				t.Errorf("got %s %s %s, want %s %s %s", name, version, arch, tt.wantName, tt.wantVersion, tt.wantArch)
+			}
+		})
+	}
+
+	if got := debianPoolLetter("libacme"); got != "liba" {
+		t.Errorf("debianPoolLetter(libacme) = %q, want liba", got)
+	}
+	if got := debianPoolLetter("acme-cli"); got != "a" {
+		t.Errorf("debianPoolLetter(acme-cli) = %q, want a", got)
+	}
+}
+
+func TestParseRPMFileName(t *testing.T) {
+	tests := []struct {
+		fileName    string
+		wantName    string
+		wantVersion string
+		wantRelease string
+		wantArch    string
+		wantErr     bool
+	}{
+		{fileName: "acme-cli-1.4.0-1.el9.x86_64.rpm", wantName: "acme-cli", wantVersion: "1.4.0", wantRelease: "1.el9", wantArch: "x86_64"},
+		{fileName: "acme-2.0.0-3.noarch.rpm", wantName: "acme", wantVersion: "2.0.0", wantRelease: "3", wantArch: "noarch"},
+		{fileName: "acme.x86_64.rpm", wantErr: true},
+		{fileName: "acme-2.0.0-3.noarch.deb", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fileName, func(t *testing.T) {
+			name, version, release, arch, err := parseRPMFileName(tt.fileName)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if name != tt.wantName || version != tt.wantVersion || release != tt.wantRelease || arch != tt.wantArch {
+				t.Errorf("got %s %s %s %s", name, version, release, arch)
+			}
+		})
+	}
+}
+
+func TestPublishDebianPackage(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+
+	tmpDir := t.TempDir()
+	chdirForTest(t, tmpDir)
+	_ = os.WriteFile("acme-cli_1.4.0_amd64.deb", []byte("deb-bytes"), 0644)
+
+	var query string
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.EscapedPath() != "/api/v4/projects/group%2Fproject/packages/debian/acme-cli_1%2E4%2E0_amd64%2Edeb" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+		}
+		query = r.URL.RawQuery
+		w.WriteHeader(http.StatusCreated)
+	})
+	client := newTestClient(t, server.URL)
+	p := &GitLabPlugin{}
+
+	published, err := p.uploadReleaseAsset(context.Background(), client, &Config{}, "group/project", plugin.ReleaseContext{TagName: "v1.4.0"}, Asset{Path: "acme-cli_1.4.0_amd64.deb", Registry: "debian", Distribution: "bookworm"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if query != "component=main&distribution=bookworm" {
+		t.Errorf("unexpected query %q", query)
+	}
+	if published.artifacts[0].Type != "debian_package" {
+		t.Errorf("unexpected artifact type %q", published.artifacts[0].Type)
+	}
+	if published.link == nil || published.link.Name != "acme-cli 1.4.0 (Debian bookworm/main)" {
+		t.Fatalf("unexpected link %+v", published.link)
+	}
+	if !contains(published.link.URL, "/packages/debian/pool/bookworm/a/acme-cli/1%2E4%2E0/acme-cli_1%2E4%2E0_amd64%2Edeb") {
+		t.Errorf("unexpected link URL %q", published.link.URL)
+	}
+
+	if _, err := p.uploadReleaseAsset(context.Background(), client, &Config{}, "group/project", plugin.ReleaseContext{}, Asset{Path: "acme-cli_1.4.0_amd64.deb", Registry: "debian"}); err == nil || !contains(err.Error(), "requires a distribution") {
+		t.Errorf("expected missing distribution error, got %v", err)
+	}
+}
+
+func TestPublishRPMPackage(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+
+	tmpDir := t.TempDir()
+	chdirForTest(t, tmpDir)
+	_ = os.WriteFile("acme-cli-1.4.0-1.x86_64.rpm", []byte("rpm-bytes"), 0644)
+	checksum := strings.TrimPrefix(sha256Digest([]byte("rpm-bytes")), "sha256:")
+
+	var contentType, uploadBody string
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch path := r.URL.EscapedPath(); {
+		case r.Method == http.MethodPost && path == "/api/v4/projects/Group%2FProject/packages/rpm":
+			body, _ := io.ReadAll(r.Body)
+			contentType, uploadBody = r.Header.Get("Content-Type"), string(body)
+			w.WriteHeader(http.StatusCreated)
+		case path == "/api/v4/projects/Group%2FProject/packages":
+			if r.URL.Query().Get("package_type") != "rpm" {
+				t.Errorf("unexpected package query %q", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`[{"id": 8, "name": "acme-cli"}, {"id": 7, "name": "acme-cli"}]`))
+		case path == "/api/v4/projects/Group%2FProject/packages/8/package_files":
+			_, _ = w.Write([]byte(`[{"id": 80, "file_name": "acme-cli-1.4.0-1.x86_64.rpm", "file_sha256": "other"}]`))
+		case path == "/api/v4/projects/Group%2FProject/packages/7/package_files":
+			_, _ = w.Write([]byte(`[{"id": 70, "file_name": "acme-cli-1.4.0-1.x86_64.rpm", "file_sha256": "` + checksum + `"}]`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, path)
+			http.NotFound(w, r)
+		}
+	})
+	client := newTestClient(t, server.URL)
+	p := &GitLabPlugin{}
+
+	published, err := p.uploadReleaseAsset(context.Background(), client, &Config{}, "Group/Project", plugin.ReleaseContext{TagName: "v1.4.0"}, Asset{Path: "acme-cli-1.4.0-1.x86_64.rpm", Registry: "rpm"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !contains(contentType, "multipart/form-data") || !contains(uploadBody, `name="file"`) {
+		t.Fatalf("expected multipart file upload, got %q", contentType)
+	}
+	if published.link == nil || published.link.Name != "acme-cli 1.4.0-1 (RPM)" || published.link.LinkType != "package" {
+		t.Fatalf("unexpected link %+v", published.link)
+	}
+	wantURL := server.URL + "/api/v4/projects/Group%2FProject/packages/rpm/70/acme-cli-1%2E4%2E0-1%2Ex86_64%2Erpm"
+	if published.link.URL != wantURL || published.artifacts[0].Path != wantURL {
+		t.Errorf("expected link URL %q, got %q", wantURL, published.link.URL)
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// plannedAsset is a file or directory that publishing would upload.
+type plannedAsset struct {
+	Path     string `json:"path"`
+	Registry string `json:"registry"`
+	Archive  string `json:"archive,omitempty"`
+	// Size is the file size, or the total size of a directory's files.
+	Size int64 `json:"size"`
+	// FileName is the name of the file in the release's generic package.
+	FileName string `json:"file_name,omitempty"`
+	// Published reports that the generic package already contains FileName.
+	Published bool `json:"published,omitempty"`
+}
+
+// planAsset validates an asset as publishing would before uploading it.
+func planAsset(asset Asset) (*plannedAsset, error) {
+	planned := &plannedAsset{Path: asset.Path, Registry: asset.Registry, Archive: asset.Archive}
+	if planned.Registry == "" {
+		planned.Registry = registryGeneric
+	}
+	if planned.Registry == registryGeneric {
+		planned.FileName = filepath.Base(asset.Path)
+		if asset.Archive != "" {
+			planned.FileName += "." + asset.Archive
+		}
+	}
+
+	// Archives, container images and Terraform modules may be directories;
+	// everything else is uploaded as a single file
+	if asset.Archive == "" && asset.Registry != registryContainer && asset.Registry != registryTerraform {
+		path, err := validateAssetFile(asset.Path)
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("asset file not accessible %s: %w", asset.Path, err)
+		}
+		planned.Size = info.Size()
+		return planned, nil
+	}
+
+	path, err := validateAssetPath(asset.Path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asset path %s: %w", asset.Path, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("asset file not accessible %s: %w", asset.Path, err)
+	}
+	if asset.Archive != "" && !info.IsDir() {
+		return nil, fmt.Errorf("archive is only supported for directories: %s", asset.Path)
+	}
+	if !info.IsDir() {
+		planned.Size = info.Size()
+		return planned, nil
+	}
+
+	err = filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		planned.Size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", asset.Path, err)
+	}
+	return planned, nil
+}
+
+// planAssets validates the configured assets as publishing would. Invalid
+// assets are returned as warnings, as publishing skips them.
+func planAssets(cfg *Config) ([]plannedAsset, []string) {
+	var warnings []string
+	assets := []plannedAsset{}
+	for _, asset := range expandAssetGlobs(cfg.Assets) {
+		planned, err := planAsset(asset)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("asset %s not published: %v", asset.Path, err))
+			continue
+		}
+		assets = append(assets, *planned)
+	}
+	return assets, warnings
+}
+
+// defaultMaxAssetSize is GitLab's default maximum file size for generic
+// packages, 5 GiB.
+const defaultMaxAssetSize int64 = 5 << 30
+
+// oversizedAssets reports the planned generic package files that are larger
+// than limit. Archives are only sized once they are built, so they are not
+// checked.
+func oversizedAssets(assets []plannedAsset, limit int64) []string {
+	var problems []string
+	for _, asset := range assets {
+		if asset.Registry == registryGeneric && asset.Archive == "" && asset.Size > limit {
+			problems = append(problems, fmt.Sprintf("asset %s is %d bytes, more than the %d bytes allowed for generic package files (max_asset_size)", asset.Path, asset.Size, limit))
+		}
+	}
+	return problems
+}
+
+// planRelease reports what publishing would do without changing anything:
+// the request that creates the release and the validated assets, which must
+// fit the generic package file size limit. With
+// dry_run_checks, read-only API calls confirm that the release can be created
+// and compare it with an already published release.
+func (p *GitLabPlugin) planRelease(ctx context.Context, client *gitlab.Client, cfg *Config, projectID string, releaseCtx plugin.ReleaseContext, opts *gitlab.CreateReleaseOptions, sbomPath string) *plugin.ExecuteResponse {
+	assets, warnings := planAssets(cfg)
+	if sbomPath != "" {
+		if info, err := os.Stat(sbomPath); err == nil {
+			name := filepath.Base(sbomPath)
+			assets = append(assets, plannedAsset{Path: name, Registry: registryGeneric, Size: info.Size(), FileName: name})
+		}
+	}
+
+	outputs := map[string]any{
+		"tag_name":    releaseCtx.TagName,
+		"project_id":  projectID,
+		"name":        *opts.Name,
+		"description": *opts.Description,
+		"assets":      assets,
+	}
+
+	limit := cfg.MaxAssetSize
+	if limit == 0 {
+		limit = defaultMaxAssetSize
+	}
+	problems := oversizedAssets(assets, limit)
+	if cfg.DryRunChecks {
+		existing, checkProblems := checkRelease(ctx, client, projectID, opts, cfg.UpdateExisting)
+		problems = append(problems, checkProblems...)
+		if existing != nil {
+			diff := diffRelease(existing, opts, prunableLinks(client, cfg))
+			diff.AssetsToUpload = assets
+			outputs["diff"] = diff
+		}
+
+		if cfg.CheckLatest && len(problems) == 0 {
+			releasedAt, _, warning, err := protectLatest(ctx, client, cfg, projectID, *opts.TagName, opts.ReleasedAt, time.Now())
+			if err != nil {
+				problems = append(problems, err.Error())
+			}
+			opts.ReleasedAt = releasedAt
+			if warning != "" {
+				warnings = append(warnings, warning)
+			}
+		}
+	}
+	outputs["request"] = opts
+
+	action := "create"
+	if _, ok := outputs["diff"]; ok {
+		action = "update"
+	}
+	if len(problems) > 0 {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("publishing %s would fail: %s", releaseCtx.TagName, strings.Join(problems, "; ")),
+			Outputs: outputs,
+		}
+	}
+	return withWarnings(&plugin.ExecuteResponse{
+		Success: true,
+		Message: fmt.Sprintf("Would %s GitLab release for %s: %s", action, projectID, releaseCtx.TagName),
+		Outputs: outputs,
+	}, warnings)
+}
+
+// checkRelease looks up what creating the release depends on. It returns the
+// already published release with the same tag, if any, and the problems that
+// would make creating the release fail. An existing release is only a problem
+// when it would not be updated.
+func checkRelease(ctx context.Context, client *gitlab.Client, projectID string, opts *gitlab.CreateReleaseOptions, update bool) (*gitlab.Release, []string) {
+	if _, _, err := client.Projects.GetProject(projectID, nil, gitlab.WithContext(ctx)); err != nil {
+		return nil, []string{fmt.Sprintf("project %s is not accessible: %v", projectID, err)}
+	}
+
+	var problems []string
+	tagName, ref := *opts.TagName, *opts.Ref
+	_, _, err := client.Tags.GetTag(projectID, tagName, gitlab.WithContext(ctx))
+	switch {
+	case errors.Is(err, gitlab.ErrNotFound) && ref == tagName:
+		problems = append(problems, fmt.Sprintf("tag %s does not exist (push it or set ref to create it)", tagName))
+	case errors.Is(err, gitlab.ErrNotFound):
+		// GitLab creates the tag from ref
+		if _, _, err := client.Commits.GetCommit(projectID, ref, nil, gitlab.WithContext(ctx)); err != nil {
+			problems = append(problems, fmt.Sprintf("tag %s does not exist and ref %s cannot be resolved: %v", tagName, ref, err))
+		}
+	case err != nil:
+		problems = append(problems, fmt.Sprintf("failed to look up tag %s: %v", tagName, err))
+	}
+
+	if opts.Milestones != nil {
+		for _, title := range *opts.Milestones {
+			milestones, _, err := client.Milestones.ListMilestones(projectID, &gitlab.ListMilestonesOptions{
+				Title:            gitlab.Ptr(title),
+				IncludeAncestors: gitlab.Ptr(true),
+			}, gitlab.WithContext(ctx))
+			switch {
+			case err != nil:
+				problems = append(problems, fmt.Sprintf("failed to look up milestone %s: %v", title, err))
+			case len(milestones) == 0:
+				problems = append(problems, fmt.Sprintf("milestone %s does not exist", title))
+			}
+		}
+	}
+
+	existing, err := getExistingRelease(ctx, client, projectID, tagName)
+	switch {
+	case err != nil:
+		problems = append(problems, err.Error())
+	case existing != nil && !update:
+		problems = append(problems, fmt.Sprintf("release %s already exists (set update_existing to update it)", tagName))
+	}
+	return existing, problems
+}
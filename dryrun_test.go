@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestPlanAsset(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "docs", "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"app.tar.gz":          "app",
+		"docs/index.html":     "<html>",
+		"docs/api/index.html": "<html></html>",
+		"chart-1.0.0.tgz":     "chart",
+	} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	chdirForTest(t, tmpDir)
+
+	tests := []struct {
+		name     string
+		asset    Asset
+		wantSize int64
+		wantErr  string
+	}{
+		{name: "generic file", asset: Asset{Path: "app.tar.gz"}, wantSize: 3},
+		{name: "registry file", asset: Asset{Path: "chart-1.0.0.tgz", Registry: registryHelm}, wantSize: 5},
+		{name: "archived directory", asset: Asset{Path: "docs", Archive: "zip"}, wantSize: 19},
+		{name: "container directory", asset: Asset{Path: "docs", Registry: registryContainer}, wantSize: 19},
+		{name: "missing file", asset: Asset{Path: "missing.zip"}, wantErr: "asset file not accessible"},
+		{name: "directory without archive", asset: Asset{Path: "docs"}, wantErr: "asset path is a directory"},
+		{name: "archived file", asset: Asset{Path: "app.tar.gz", Archive: "zip"}, wantErr: "archive is only supported for directories"},
+		{name: "outside working directory", asset: Asset{Path: "../secret"}, wantErr: "path traversal not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			planned, err := planAsset(tt.asset)
+			if tt.wantErr != "" {
+				if err == nil || !contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("planAsset returned error: %v", err)
+			}
+			if planned.Size != tt.wantSize {
+				t.Errorf("expected size %d, got %d", tt.wantSize, planned.Size)
+			}
+			if planned.Registry == "" {
+				t.Error("expected a registry")
+			}
+		})
+	}
+}
+
+func TestExecuteDryRunPlan(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "app.tar.gz"), []byte("app"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdirForTest(t, tmpDir)
+
+	tests := []struct {
+		name         string
+		checks       bool
+		maxAssetSize int
+		tagExists    bool
+		ref          string
+		existing     bool
+		wantSuccess  bool
+		wantError    []string
+		wantDiff     bool
+		wantCalls    bool
+	}{
+		{name: "without checks", wantSuccess: true},
+		{name: "checks pass", checks: true, tagExists: true, wantSuccess: true, wantCalls: true},
+		{name: "tag created from ref", checks: true, ref: "main", wantSuccess: true, wantCalls: true},
+		{name: "asset within max_asset_size", maxAssetSize: 3, wantSuccess: true},
+		{name: "asset larger than max_asset_size", maxAssetSize: 2, wantError: []string{"asset app.tar.gz is 3 bytes, more than the 2 bytes allowed"}},
+		{
+			name:      "missing tag and milestone",
+			checks:    true,
+			wantError: []string{"tag v1.4.0 does not exist", "milestone 1.5 does not exist"},
+			wantCalls: true,
+		},
+		{
+			name:      "release exists",
+			checks:    true,
+			tagExists: true,
+			existing:  true,
+			wantError: []string{"release v1.4.0 already exists"},
+			wantDiff:  true,
+			wantCalls: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					t.Errorf("dry run sent %s %s", r.Method, r.URL.Path)
+					http.Error(w, "unexpected", http.StatusMethodNotAllowed)
+					return
+				}
+				calls = append(calls, r.URL.Path)
+				switch {
+				case contains(r.URL.Path, "/repository/tags/"):
+					if !tt.tagExists {
+						http.NotFound(w, r)
+						return
+					}
+					_, _ = w.Write([]byte(`{"name": "v1.4.0"}`))
+				case contains(r.URL.Path, "/repository/commits/main"):
+					_, _ = w.Write([]byte(`{"id": "abc123"}`))
+				case contains(r.URL.Path, "/milestones"):
+					if r.URL.Query().Get("title") != "1.4" {
+						_, _ = w.Write([]byte(`[]`))
+						return
+					}
+					_, _ = w.Write([]byte(`[{"id": 3, "title": "1.4"}]`))
+				case contains(r.URL.Path, "/releases/"):
+					if !tt.existing {
+						http.NotFound(w, r)
+						return
+					}
+					_, _ = w.Write([]byte(`{"tag_name": "v1.4.0", "name": "Release 1.4.0", "description": "Old notes", "assets": {"links": []}}`))
+				case r.URL.Path == "/api/v4/projects/acme/app":
+					_, _ = w.Write([]byte(`{"id": 1, "path_with_namespace": "acme/app"}`))
+				default:
+					http.NotFound(w, r)
+				}
+			})
+
+			milestones := []any{"1.4"}
+			if len(tt.wantError) > 1 {
+				milestones = append(milestones, "1.5")
+			}
+			config := map[string]any{
+				"token":          "glpat-test",
+				"base_url":       server.URL,
+				"project_id":     "acme/app",
+				"milestones":     milestones,
+				"assets":         []any{"app.tar.gz", "missing.zip"},
+				"asset_links":    []any{map[string]any{"name": "Docs", "url": "https://docs.example.com"}},
+				"dry_run_checks": tt.checks,
+			}
+			if tt.ref != "" {
+				config["ref"] = tt.ref
+			}
+			if tt.maxAssetSize > 0 {
+				config["max_asset_size"] = float64(tt.maxAssetSize)
+			}
+
+			p := &GitLabPlugin{}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook:    plugin.HookPostPublish,
+				Config:  config,
+				Context: plugin.ReleaseContext{Version: "1.4.0", TagName: "v1.4.0", ReleaseNotes: "Notes"},
+				DryRun:  true,
+			})
+			if err != nil {
+				t.Fatalf("Execute returned error: %v", err)
+			}
+			if resp.Success != tt.wantSuccess {
+				t.Fatalf("expected success %v, got %+v", tt.wantSuccess, resp)
+			}
+			for _, want := range tt.wantError {
+				if !contains(resp.Error, want) {
+					t.Errorf("expected error containing %q, got %q", want, resp.Error)
+				}
+			}
+			if tt.wantSuccess && !contains(resp.Message, "asset missing.zip not published") {
+				t.Errorf("expected a warning for the missing asset, got %q", resp.Message)
+			}
+			if (len(calls) > 0) != tt.wantCalls {
+				t.Errorf("unexpected API calls %v", calls)
+			}
+
+			request := resp.Outputs["request"].(*gitlab.CreateReleaseOptions)
+			if *request.Description != "Notes" || len(*request.Milestones) != len(milestones) || len(request.Assets.Links) != 1 {
+				t.Errorf("unexpected planned request %+v", request)
+			}
+			if resp.Outputs["description"] != "Notes" {
+				t.Errorf("unexpected description %v", resp.Outputs["description"])
+			}
+			assets := resp.Outputs["assets"].([]plannedAsset)
+			if len(assets) != 1 || assets[0].Path != "app.tar.gz" || assets[0].Size != 3 || assets[0].Registry != registryGeneric {
+				t.Errorf("unexpected planned assets %+v", assets)
+			}
+
+			diff, ok := resp.Outputs["diff"].(*releaseDiff)
+			if ok != tt.wantDiff {
+				t.Fatalf("diff present = %v, want %v", ok, tt.wantDiff)
+			}
+			if ok && (diff.Description == nil || diff.Description.From != "Old notes" || len(diff.LinksAdded) != 1) {
+				t.Errorf("unexpected diff %+v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// failureIssueLabel marks issues opened for failed releases. An open issue
+// with this label for the same tag is commented on instead of opening another one.
+const failureIssueLabel = "release-failure"
+
+// failureIssueMarker identifies the tag an issue was opened for. It is
+// appended to the description as an HTML comment, which GitLab does not render.
+func failureIssueMarker(releaseCtx plugin.ReleaseContext) string {
+	tag := releaseCtx.TagName
+	if tag == "" {
+		tag = releaseCtx.Version
+	}
+	return fmt.Sprintf("<!-- relicta-release-failure: %s -->", tag)
+}
+
+// findFailureIssue returns the open release-failure issue opened for the
+// release's tag, or nil if there is none.
+func findFailureIssue(ctx context.Context, client *gitlab.Client, projectID string, releaseCtx plugin.ReleaseContext) (*gitlab.Issue, error) {
+	marker := failureIssueMarker(releaseCtx)
+	opts := &gitlab.ListProjectIssuesOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 100},
+		State:       gitlab.Ptr("opened"),
+		Labels:      &gitlab.LabelOptions{failureIssueLabel},
+		OrderBy:     gitlab.Ptr("created_at"),
+		Sort:        gitlab.Ptr("desc"),
+	}
+	for {
+		issues, resp, err := client.Issues.ListProjectIssues(projectID, opts, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list release failure issues: %w", err)
+		}
+		for _, issue := range issues {
+			if strings.Contains(issue.Description, marker) {
+				return issue, nil
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			return nil, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// failureErrorEnv is read for the failure message. The on_error hook does not
+// carry the error itself, so this is only set when the runner exports it.
+const failureErrorEnv = "RELICTA_ERROR"
+
+const (
+	defaultFailureIssueTitle       = "Release {version} failed"
+	defaultFailureIssueDescription = "The release of {version} ({tag_name}) failed.\n\n" +
+		"- Branch: {branch}\n" +
+		"- Commit: {commit_sha}\n" +
+		"- Job: {job_url}\n\n" +
+		"```\n{error}\n```"
+)
+
+// renderFailureTemplate expands the release placeholders plus {error} and
+// {job_url}, which are only meaningful for failure issues.
+func renderFailureTemplate(tmpl string, releaseCtx plugin.ReleaseContext) string {
+	errText := envValue(releaseCtx, failureErrorEnv)
+	if errText == "" {
+		errText = "No error message was provided; see the job log."
+	}
+	jobURL := envValue(releaseCtx, "CI_JOB_URL")
+	if jobURL == "" {
+		jobURL = "unknown"
+	}
+	return strings.NewReplacer(
+		"{error}", errText,
+		"{job_url}", jobURL,
+	).Replace(renderTemplate(tmpl, releaseCtx))
+}
+
+// resolveAssignees looks up user IDs by username. Unknown usernames are
+// returned separately so the issue is still opened without them.
+func resolveAssignees(ctx context.Context, client *gitlab.Client, usernames []string) ([]int64, []string, error) {
+	var ids []int64
+	var unknown []string
+	for _, username := range usernames {
+		users, _, err := client.Users.ListUsers(&gitlab.ListUsersOptions{Username: gitlab.Ptr(username)}, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to look up user %s: %w", username, err)
+		}
+		if len(users) == 0 {
+			unknown = append(unknown, username)
+			continue
+		}
+		ids = append(ids, users[0].ID)
+	}
+	return ids, unknown, nil
+}
+
+// reportFailure opens an issue for the failed release, or comments on the
+// open release-failure issue for the same tag if there is one.
+func (p *GitLabPlugin) reportFailure(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool) (*plugin.ExecuteResponse, error) {
+	client, err := p.getClient(cfg)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to create GitLab client: %v", err),
+		}, nil
+	}
+
+	projectID := resolveProjectID(cfg, releaseCtx)
+	if projectID == "" {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   "project_id is required (set in config or provide repository owner/name)",
+		}, nil
+	}
+
+	title := cfg.FailureIssueTitle
+	if title == "" {
+		title = defaultFailureIssueTitle
+	}
+	description := cfg.FailureIssueDescription
+	if description == "" {
+		description = defaultFailureIssueDescription
+	}
+	title = renderFailureTemplate(title, releaseCtx)
+	description = renderFailureTemplate(description, releaseCtx)
+
+	if dryRun {
+		return &plugin.ExecuteResponse{
+			Success: true,
+			Message: fmt.Sprintf("Would report release failure in an issue titled %q", title),
+		}, nil
+	}
+
+	issue, err := findFailureIssue(ctx, client, projectID, releaseCtx)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	if issue != nil {
+		body := fmt.Sprintf("**%s**\n\n%s", title, description)
+		if _, _, err := client.Notes.CreateIssueNote(projectID, issue.IID, &gitlab.CreateIssueNoteOptions{
+			Body: gitlab.Ptr(body),
+		}, gitlab.WithContext(ctx)); err != nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   fmt.Sprintf("failed to comment on issue #%d: %v", issue.IID, err),
+			}, nil
+		}
+		return &plugin.ExecuteResponse{
+			Success: true,
+			Message: fmt.Sprintf("Commented on release failure issue #%d", issue.IID),
+			Outputs: map[string]any{
+				"issue_iid": issue.IID,
+				"issue_url": issue.WebURL,
+			},
+		}, nil
+	}
+
+	assigneeIDs, unknown, err := resolveAssignees(ctx, client, cfg.FailureIssueAssignees)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	opts := &gitlab.CreateIssueOptions{
+		Title:       gitlab.Ptr(title),
+		Description: gitlab.Ptr(description + "\n\n" + failureIssueMarker(releaseCtx)),
+		Labels:      &gitlab.LabelOptions{failureIssueLabel},
+	}
+	if len(assigneeIDs) > 0 {
+		opts.AssigneeIDs = &assigneeIDs
+	}
+	issue, _, err = client.Issues.CreateIssue(projectID, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to create release failure issue: %v", err),
+		}, nil
+	}
+
+	message := fmt.Sprintf("Opened release failure issue #%d", issue.IID)
+	if len(unknown) > 0 {
+		message += fmt.Sprintf(" (unknown assignees: %s)", strings.Join(unknown, ", "))
+	}
+	return &plugin.ExecuteResponse{
+		Success: true,
+		Message: message,
+		Outputs: map[string]any{
+			"issue_iid": issue.IID,
+			"issue_url": issue.WebURL,
+		},
+	}, nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestRenderFailureTemplate(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{
+		Version:   "1.4.0",
+		TagName:   "v1.4.0",
+		Branch:    "main",
+		CommitSHA: "abc123",
+		Environment: map[string]string{
+			"RELICTA_ERROR": "upload failed: 500",
+			"CI_JOB_URL":    "https://gitlab.example.com/group/project/-/jobs/42",
+		},
+	}
+
+	got := renderFailureTemplate(defaultFailureIssueDescription, releaseCtx)
+	for _, want := range []string{"The release of 1.4.0 (v1.4.0) failed.", "- Branch: main", "- Commit: abc123", "/-/jobs/42", "upload failed: 500"} {
+		if !contains(got, want) {
+			t.Errorf("expected description to contain %q, got:\n%s", want, got)
+		}
+	}
+
+	// Without an error message or job URL the placeholders are still filled
+	t.Setenv("RELICTA_ERROR", "")
+	t.Setenv("CI_JOB_URL", "")
+	got = renderFailureTemplate("{error} ({job_url})", plugin.ReleaseContext{})
+	if got != "No error message was provided; see the job log. (unknown)" {
+		t.Errorf("unexpected fallback rendering %q", got)
+	}
+}
+
+func TestReportFailure(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{
+		Version:     "1.4.0",
+		TagName:     "v1.4.0",
+		Branch:      "main",
+		CommitSHA:   "abc123",
+		Environment: map[string]string{"RELICTA_ERROR": "upload failed"},
+	}
+
+	tests := []struct {
+		name         string
+		openIssues   string
+		dryRun       bool
+		wantMessage  string
+		wantCreate   bool
+		wantNote     bool
+		wantAssignee []any
+	}{
+		{
+			name:         "opens a new issue",
+			openIssues:   `[]`,
+			wantMessage:  "Opened release failure issue #7 (unknown assignees: ghost)",
+			wantCreate:   true,
+			wantAssignee: []any{float64(11)},
+		},
+		{
+			name: "comments on the open issue for the tag",
+			openIssues: `[
+				{"id": 92, "iid": 6, "description": "Failed\n\n<!-- relicta-release-failure: v1.3.0 -->"},
+				{"id": 90, "iid": 5, "description": "Failed\n\n<!-- relicta-release-failure: v1.4.0 -->", "web_url": "https://gitlab.example.com/group/project/-/issues/5"}
+			]`,
+			wantMessage: "Commented on release failure issue #5",
+			wantNote:    true,
+		},
+		{
+			name:         "opens a new issue when open issues are for other tags",
+			openIssues:   `[{"id": 92, "iid": 6, "description": "Failed\n\n<!-- relicta-release-failure: v1.3.0 -->"}, {"id": 93, "iid": 4, "description": "Opened by hand"}]`,
+			wantMessage:  "Opened release failure issue #7 (unknown assignees: ghost)",
+			wantCreate:   true,
+			wantAssignee: []any{float64(11)},
+		},
+		{
+			name:        "dry run",
+			dryRun:      true,
+			wantMessage: `Would report release failure in an issue titled "Release 1.4.0 failed"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created, note map[string]any
+			var listQuery string
+			server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodGet && contains(r.URL.Path, "/users"):
+					if r.URL.Query().Get("username") == "alice" {
+						_, _ = w.Write([]byte(`[{"id": 11, "username": "alice"}]`))
+						return
+					}
+					_, _ = w.Write([]byte(`[]`))
+				case r.Method == http.MethodGet && contains(r.URL.Path, "/issues"):
+					listQuery = r.URL.RawQuery
+					_, _ = w.Write([]byte(tt.openIssues))
+				case r.Method == http.MethodPost && contains(r.URL.Path, "/notes"):
+					_ = json.NewDecoder(r.Body).Decode(&note)
+					w.WriteHeader(http.StatusCreated)
+					_, _ = w.Write([]byte(`{"id": 1}`))
+				case r.Method == http.MethodPost && contains(r.URL.Path, "/issues"):
+					_ = json.NewDecoder(r.Body).Decode(&created)
+					w.WriteHeader(http.StatusCreated)
+					_, _ = w.Write([]byte(`{"id": 91, "iid": 7, "web_url": "https://gitlab.example.com/group/project/-/issues/7"}`))
+				default:
+					http.NotFound(w, r)
+				}
+			})
+
+			cfg := &Config{
+				Token:                 "glpat-test",
+				BaseURL:               server.URL,
+				ProjectID:             "group/project",
+				FailureIssue:          true,
+				FailureIssueAssignees: []string{"alice", "ghost"},
+			}
+
+			p := &GitLabPlugin{}
+			resp, err := p.reportFailure(context.Background(), cfg, releaseCtx, tt.dryRun)
+			if err != nil {
+				t.Fatalf("reportFailure returned error: %v", err)
+			}
+			if !resp.Success || resp.Message != tt.wantMessage {
+				t.Fatalf("unexpected response %+v", resp)
+			}
+
+			if !tt.dryRun && (!contains(listQuery, "labels=release-failure") || !contains(listQuery, "state=opened")) {
+				t.Errorf("expected open release-failure issues to be listed, got query %q", listQuery)
+			}
+			if (created != nil) != tt.wantCreate {
+				t.Fatalf("issue created = %v, want %v", created != nil, tt.wantCreate)
+			}
+			if tt.wantCreate {
+				if description, _ := created["description"].(string); created["title"] != "Release 1.4.0 failed" || !contains(description, "upload failed") || !contains(description, "<!-- relicta-release-failure: v1.4.0 -->") {
+					t.Errorf("unexpected issue %v", created)
+				}
+				if labels, _ := created["labels"].(string); labels != "release-failure" {
+					t.Errorf("expected release-failure label, got %v", created["labels"])
+				}
+				if assignees, _ := created["assignee_ids"].([]any); len(assignees) != 1 || assignees[0] != tt.wantAssignee[0] {
+					t.Errorf("expected assignees %v, got %v", tt.wantAssignee, created["assignee_ids"])
+				}
+			}
+			if (note != nil) != tt.wantNote {
+				t.Fatalf("note created = %v, want %v", note != nil, tt.wantNote)
+			}
+			if tt.wantNote && !contains(note["body"].(string), "**Release 1.4.0 failed**") {
+				t.Errorf("unexpected note %v", note)
+			}
+		})
+	}
+}
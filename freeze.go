@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// activeFreeze describes a deploy freeze that is in effect.
+type activeFreeze struct {
+	period *gitlab.FreezePeriod
+	// ends is the end of the freeze; zero when no end was found.
+	ends time.Time
+}
+
+// String describes the freeze for messages.
+func (f activeFreeze) String() string {
+	desc := fmt.Sprintf("freeze period %d (%q to %q", f.period.ID, f.period.FreezeStart, f.period.FreezeEnd)
+	if f.period.CronTimezone != "" {
+		desc += " " + f.period.CronTimezone
+	}
+	desc += ")"
+	if !f.ends.IsZero() {
+		desc += ", ends " + f.ends.Format(time.RFC3339)
+	}
+	return desc
+}
+
+// findActiveFreeze reports whether now falls inside the freeze period. A
+// freeze is active when its most recent start is later than its most recent end.
+func findActiveFreeze(period *gitlab.FreezePeriod, now time.Time) (*activeFreeze, error) {
+	loc := time.UTC
+	if period.CronTimezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(period.CronTimezone); err != nil {
+			return nil, fmt.Errorf("freeze period %d has invalid timezone %q: %w", period.ID, period.CronTimezone, err)
+		}
+	}
+
+	start, err := parseCron(period.FreezeStart)
+	if err != nil {
+		return nil, fmt.Errorf("freeze period %d: %w", period.ID, err)
+	}
+	end, err := parseCron(period.FreezeEnd)
+	if err != nil {
+		return nil, fmt.Errorf("freeze period %d: %w", period.ID, err)
+	}
+
+	now = now.In(loc)
+	lastStart, ok := start.prev(now)
+	if !ok {
+		return nil, nil
+	}
+	if lastEnd, ok := end.prev(now); ok && !lastEnd.Before(lastStart) {
+		return nil, nil
+	}
+
+	freeze := &activeFreeze{period: period}
+	if ends, ok := end.next(now); ok {
+		freeze.ends = ends
+	}
+	return freeze, nil
+}
+
+// checkFreezePeriods fails the run when the project is inside one of its
+// deploy freeze periods, unless override_freeze is set.
+func (p *GitLabPlugin) checkFreezePeriods(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, now time.Time) (*plugin.ExecuteResponse, error) {
+	client, err := p.getClient(cfg)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to create GitLab client: %v", err),
+		}, nil
+	}
+
+	projectID := resolveProjectID(cfg, releaseCtx)
+	if projectID == "" {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   "project_id is required (set in config or provide repository owner/name)",
+		}, nil
+	}
+
+	var active []string
+	opts := &gitlab.ListFreezePeriodsOptions{ListOptions: gitlab.ListOptions{PerPage: 100}}
+	for {
+		periods, resp, err := client.FreezePeriods.ListFreezePeriods(projectID, opts, gitlab.WithContext(ctx))
+		if err != nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   fmt.Sprintf("failed to list freeze periods: %v", err),
+			}, nil
+		}
+
+		for _, period := range periods {
+			freeze, err := findActiveFreeze(period, now)
+			if err != nil {
+				return &plugin.ExecuteResponse{
+					Success: false,
+					Error:   fmt.Sprintf("failed to evaluate freeze periods: %v", err),
+				}, nil
+			}
+			if freeze != nil {
+				active = append(active, freeze.String())
+			}
+		}
+
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	if len(active) == 0 {
+		return &plugin.ExecuteResponse{
+			Success: true,
+			Message: fmt.Sprintf("No deploy freeze in effect for %s", projectID),
+		}, nil
+	}
+
+	summary := strings.Join(active, "; ")
+	if cfg.OverrideFreeze {
+		return &plugin.ExecuteResponse{
+			Success: true,
+			Message: fmt.Sprintf("Deploy freeze overridden for %s: %s", projectID, summary),
+		}, nil
+	}
+
+	return &plugin.ExecuteResponse{
+		Success: false,
+		Error:   fmt.Sprintf("deploy freeze in effect for %s: %s (set override_freeze to publish anyway)", projectID, summary),
+	}, nil
+}
+
+// checkDeployFreezes checks the deploy freeze periods of every project the
+// release is published to: each target of each component, or the top-level
+// project.
+func (p *GitLabPlugin) checkDeployFreezes(ctx context.Context, raw map[string]any, cfg *Config, releaseCtx plugin.ReleaseContext, now time.Time) (*plugin.ExecuteResponse, error) {
+	var steps []hookStep
+	checked := map[string]bool{}
+	for _, project := range p.releaseProjects(raw, cfg, releaseCtx) {
+		if project.err != nil {
+			err := project.err
+			steps = append(steps, func() (*plugin.ExecuteResponse, error) {
+				return &plugin.ExecuteResponse{Success: false, Error: err.Error()}, nil
+			})
+			continue
+		}
+
+		key := normalizeBaseURL(project.cfg.BaseURL) + " " + resolveProjectID(project.cfg, project.releaseCtx)
+		if checked[key] {
+			continue
+		}
+		checked[key] = true
+		steps = append(steps, func() (*plugin.ExecuteResponse, error) {
+			return p.checkFreezePeriods(ctx, project.cfg, project.releaseCtx, now)
+		})
+	}
+	return runSteps(steps)
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestFindActiveFreeze(t *testing.T) {
+	weekend := &gitlab.FreezePeriod{ID: 1, FreezeStart: "0 23 * * 5", FreezeEnd: "0 7 * * 1", CronTimezone: "Europe/Berlin"}
+
+	tests := []struct {
+		name       string
+		period     *gitlab.FreezePeriod
+		now        time.Time
+		wantActive bool
+		wantEnds   string
+	}{
+		{
+			name:   "before freeze starts",
+			period: weekend,
+			// Friday 21:30 UTC is 22:30 in Berlin
+			now: time.Date(2025, 3, 7, 21, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "inside weekend freeze",
+			period:     weekend,
+			now:        time.Date(2025, 3, 8, 12, 0, 0, 0, time.UTC),
+			wantActive: true,
+			wantEnds:   "2025-03-10T07:00:00+01:00",
+		},
+		{
+			name:   "after freeze ends",
+			period: weekend,
+			now:    time.Date(2025, 3, 10, 6, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "end-of-quarter freeze in UTC",
+			period:     &gitlab.FreezePeriod{ID: 2, FreezeStart: "0 0 20 3,6,9,12 *", FreezeEnd: "0 0 2 1,4,7,10 *"},
+			now:        time.Date(2025, 3, 28, 9, 0, 0, 0, time.UTC),
+			wantActive: true,
+			wantEnds:   "2025-04-02T00:00:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			freeze, err := findActiveFreeze(tt.period, tt.now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (freeze != nil) != tt.wantActive {
+				t.Fatalf("active = %v, want %v", freeze != nil, tt.wantActive)
+			}
+			if freeze != nil && freeze.ends.Format(time.RFC3339) != tt.wantEnds {
+				t.Errorf("ends = %s, want %s", freeze.ends.Format(time.RFC3339), tt.wantEnds)
+			}
+		})
+	}
+
+	if _, err := findActiveFreeze(&gitlab.FreezePeriod{ID: 3, FreezeStart: "0 0 * * *", FreezeEnd: "0 1 * * *", CronTimezone: "Mars/Olympus"}, time.Now()); err == nil {
+		t.Error("expected error for invalid timezone")
+	}
+}
+
+func TestCheckFreezePeriods(t *testing.T) {
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v4/projects/group/project/freeze_periods" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode([]gitlab.FreezePeriod{
+			{ID: 7, FreezeStart: "0 0 20 12 *", FreezeEnd: "0 0 2 1 *", CronTimezone: "UTC"},
+		})
+	})
+
+	p := &GitLabPlugin{}
+	releaseCtx := plugin.ReleaseContext{RepositoryOwner: "group", RepositoryName: "project"}
+	frozen := time.Date(2025, 12, 24, 10, 0, 0, 0, time.UTC)
+	open := time.Date(2025, 11, 24, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		cfg         *Config
+		now         time.Time
+		wantSuccess bool
+		wantText    string
+	}{
+		{name: "outside freeze", cfg: &Config{}, now: open, wantSuccess: true, wantText: "No deploy freeze"},
+		{name: "inside freeze", cfg: &Config{}, now: frozen, wantText: "set override_freeze"},
+		{name: "inside freeze with override", cfg: &Config{OverrideFreeze: true}, now: frozen, wantSuccess: true, wantText: "Deploy freeze overridden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Token = "glpat-test"
+			tt.cfg.BaseURL = server.URL
+
+			resp, err := p.checkFreezePeriods(context.Background(), tt.cfg, releaseCtx, tt.now)
+			if err != nil {
+				t.Fatalf("checkFreezePeriods returned error: %v", err)
+			}
+			if resp.Success != tt.wantSuccess {
+				t.Fatalf("expected Success=%v, got %+v", tt.wantSuccess, resp)
+			}
+			if got := resp.Message + resp.Error; !contains(got, tt.wantText) {
+				t.Errorf("expected %q in %q", tt.wantText, got)
+			}
+			if !tt.wantSuccess && !contains(resp.Error, "freeze period 7") {
+				t.Errorf("expected freeze period details in %q", resp.Error)
+			}
+		})
+	}
+}
+
+func TestExecutePrePublishFreeze(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		config       map[string]any
+		frozen       string
+		wantSuccess  bool
+		wantText     string
+		wantProjects []string
+	}{
+		{
+			name:        "not configured",
+			config:      map[string]any{},
+			wantSuccess: true,
+			wantText:    "not handled",
+		},
+		{
+			name:         "top-level project",
+			config:       map[string]any{"check_freeze_periods": true, "project_id": "group/project"},
+			frozen:       "group/project",
+			wantText:     "deploy freeze in effect for group/project",
+			wantProjects: []string{"group/project"},
+		},
+		{
+			name: "every target",
+			config: map[string]any{
+				"check_freeze_periods": true,
+				"project_id":           "group/project",
+				"targets": []any{
+					map[string]any{"project_id": "group/project"},
+					map[string]any{"project_id": "mirror/project"},
+				},
+			},
+			frozen:       "mirror/project",
+			wantText:     "deploy freeze in effect for mirror/project",
+			wantProjects: []string{"group/project", "mirror/project"},
+		},
+		{
+			name: "every component",
+			config: map[string]any{
+				"check_freeze_periods": true,
+				"project_id":           "group/project",
+				"components": []any{
+					map[string]any{"name": "sdk"},
+					map[string]any{"name": "cli", "overrides": map[string]any{"project_id": "group/cli"}},
+				},
+			},
+			wantSuccess:  true,
+			wantText:     "No deploy freeze in effect for group/project; No deploy freeze in effect for group/cli",
+			wantProjects: []string{"group/project", "group/cli"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			var projects []string
+			server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+				project := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v4/projects/"), "/freeze_periods")
+				mu.Lock()
+				projects = append(projects, project)
+				mu.Unlock()
+
+				periods := []gitlab.FreezePeriod{}
+				if project == tt.frozen {
+					// A freeze that started and never ends
+					periods = append(periods, gitlab.FreezePeriod{ID: 1, FreezeStart: "* * * * *", FreezeEnd: "0 0 30 2 *"})
+				}
+				_ = json.NewEncoder(w).Encode(periods)
+			})
+
+			// Without the check no credentials are needed
+			config := map[string]any{}
+			if len(tt.wantProjects) > 0 {
+				config = map[string]any{"token": "glpat-test", "base_url": server.URL}
+			}
+			for key, value := range tt.config {
+				config[key] = value
+			}
+
+			p := &GitLabPlugin{}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook:    plugin.HookPrePublish,
+				Config:  config,
+				Context: plugin.ReleaseContext{Version: "1.0.0", TagName: "v1.0.0"},
+			})
+			if err != nil {
+				t.Fatalf("Execute returned error: %v", err)
+			}
+			if resp.Success != tt.wantSuccess {
+				t.Fatalf("expected Success=%v, got %+v", tt.wantSuccess, resp)
+			}
+			if got := resp.Message + resp.Error; !contains(got, tt.wantText) {
+				t.Errorf("expected %q in %q", tt.wantText, got)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if strings.Join(projects, ",") != strings.Join(tt.wantProjects, ",") {
+				t.Errorf("expected freeze periods of %v, got %v", tt.wantProjects, projects)
+			}
+		})
+	}
+}
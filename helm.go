@@ -0,0 +1,163 @@
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// registryHelm is the asset registry value for Helm charts.
+const registryHelm = "helm"
+
+// Helm channels used when no channel is configured.
+const (
+	helmChannelStable = "stable"
+	helmChannelBeta   = "beta"
+)
+
+// helmChart holds the chart metadata read from Chart.yaml.
+type helmChart struct {
+	Name    string
+	Version string
+}
+
+// helmChannel returns the channel to publish a chart to. An explicit channel
+// wins; otherwise prereleases go to "beta" and everything else to "stable".
+func helmChannel(configured string, releaseCtx plugin.ReleaseContext) string {
+	if configured != "" {
+		return configured
+	}
+	if strings.Contains(strings.ToLower(releaseCtx.ReleaseType), "pre") {
+		return helmChannelBeta
+	}
+	if v, err := parseSemver(releaseCtx.Version); err == nil && v.IsPrerelease() {
+		return helmChannelBeta
+	}
+	return helmChannelStable
+}
+
+// readHelmChart reads the name and version from the Chart.yaml of a packaged chart.
+func readHelmChart(path string) (*helmChart, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("helm chart is not a gzip tarball: %w", err)
+	}
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read helm chart: %w", err)
+		}
+		// Chart.yaml lives directly below the chart's top-level directory
+		parts := strings.Split(hdr.Name, "/")
+		if len(parts) != 2 || parts[1] != "Chart.yaml" {
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		chart := parseChartYAML(data)
+		if chart.Name == "" || chart.Version == "" {
+			return nil, fmt.Errorf("Chart.yaml must declare name and version")
+		}
+		return chart, nil
+	}
+
+	return nil, fmt.Errorf("Chart.yaml not found in helm chart")
+}
+
+// parseChartYAML extracts the top-level name and version keys from Chart.yaml.
+// Only these scalar keys are needed, so a full YAML parser is not required.
+func parseChartYAML(data []byte) *helmChart {
+	chart := &helmChart{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		switch strings.TrimSpace(key) {
+		case "name":
+			chart.Name = value
+		case "version":
+			chart.Version = value
+		}
+	}
+	return chart
+}
+
+// publishHelmChart uploads a packaged chart to the project's Helm repository.
+func (p *GitLabPlugin) publishHelmChart(ctx context.Context, client *gitlab.Client, projectID string, releaseCtx plugin.ReleaseContext, asset Asset) (*publishedAsset, error) {
+	chartPath, err := validateAssetFile(asset.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	chart, err := readHelmChart(chartPath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(chartPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read helm chart: %w", err)
+	}
+
+	channel := helmChannel(asset.Channel, releaseCtx)
+	project := gitlab.PathEscape(projectID)
+	uploadPath := fmt.Sprintf("projects/%s/packages/helm/api/%s/charts", project, gitlab.PathEscape(channel))
+
+	fileName := filepath.Base(chartPath)
+	req, err := client.UploadRequest(http.MethodPost, uploadPath, bytes.NewReader(data), fileName, gitlab.UploadType("chart"), nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := client.Do(req, nil); err != nil {
+		return nil, fmt.Errorf("failed to publish helm chart %s: %w", fileName, err)
+	}
+
+	chartURL := fmt.Sprintf("%sprojects/%s/packages/helm/%s/charts/%s", client.BaseURL().String(), project, gitlab.PathEscape(channel), gitlab.PathEscape(fileName))
+	return &publishedAsset{
+		artifacts: []plugin.Artifact{{
+			Name:     fileName,
+			Path:     chartURL,
+			Type:     "helm_chart",
+			Size:     int64(len(data)),
+			Checksum: sha256Digest(data),
+		}},
+		link: &AssetLink{
+			Name:     fmt.Sprintf("%s %s (Helm, %s)", chart.Name, chart.Version, channel),
+			URL:      chartURL,
+			LinkType: "package",
+		},
+	}, nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestHelmChannel(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		releaseCtx plugin.ReleaseContext
+		want       string
+	}{
+		{name: "stable release", releaseCtx: plugin.ReleaseContext{Version: "1.2.0", ReleaseType: "minor"}, want: "stable"},
+		{name: "prerelease version", releaseCtx: plugin.ReleaseContext{Version: "1.2.0-rc.1"}, want: "beta"},
+		{name: "prerelease release type", releaseCtx: plugin.ReleaseContext{Version: "1.2.0", ReleaseType: "prerelease"}, want: "beta"},
+		{name: "explicit channel", configured: "edge", releaseCtx: plugin.ReleaseContext{Version: "1.2.0-rc.1"}, want: "edge"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := helmChannel(tt.configured, tt.releaseCtx); got != tt.want {
+				t.Errorf("helmChannel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadHelmChart(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	valid := filepath.Join(tmpDir, "api-1.2.0.tgz")
+	writeTarGz(t, valid, map[string]string{
+		"api/Chart.yaml":        "apiVersion: v2\nname: api\nversion: \"1.2.0\"\ndependencies:\n  - name: redis\n    version: 17.0.0\n",
+		"api/charts/redis.yaml": "name: redis\n",
+	})
+	missing := filepath.Join(tmpDir, "missing.tgz")
+	writeTarGz(t, missing, map[string]string{"api/values.yaml": "replicas: 1\n"})
+	notGzip := filepath.Join(tmpDir, "plain.tgz")
+	_ = os.WriteFile(notGzip, []byte("not a chart"), 0644)
+
+	chart, err := readHelmChart(valid)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if chart.Name != "api" || chart.Version != "1.2.0" {
+		t.Errorf("unexpected chart %+v", chart)
+	}
+
+	if _, err := readHelmChart(missing); err == nil || !contains(err.Error(), "Chart.yaml not found") {
+		t.Errorf("expected missing Chart.yaml error, got %v", err)
+	}
+	if _, err := readHelmChart(notGzip); err == nil || !contains(err.Error(), "gzip") {
+		t.Errorf("expected gzip error, got %v", err)
+	}
+}
+
+func TestPublishHelmChart(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+
+	tmpDir := t.TempDir()
+	chdirForTest(t, tmpDir)
+	if err := os.MkdirAll("dist", 0755); err != nil {
+		t.Fatalf("failed to create dist: %v", err)
+	}
+	for _, name := range []string{"api", "worker"} {
+		writeTarGz(t, filepath.Join("dist", name+"-1.3.0-rc.1.tgz"), map[string]string{
+			name + "/Chart.yaml": "name: " + name + "\nversion: 1.3.0-rc.1\n",
+		})
+	}
+
+	client, requests := recordingServer(t)
+	p := &GitLabPlugin{}
+	releaseCtx := plugin.ReleaseContext{Version: "1.3.0-rc.1", TagName: "v1.3.0-rc.1"}
+
+	var links []*AssetLink
+	for _, asset := range expandAssetGlobs([]Asset{{Path: "dist/*.tgz", Registry: "helm"}}) {
+		published, err := p.uploadReleaseAsset(context.Background(), client, &Config{}, "group/project", releaseCtx, asset)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		links = append(links, published.link)
+	}
+
+	reqs := requests()
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 uploads, got %d", len(reqs))
+	}
+	for _, req := range reqs {
+		if req.method != http.MethodPost || req.path != "/api/v4/projects/group%2Fproject/packages/helm/api/beta/charts" {
+			t.Errorf("unexpected request %s %s", req.method, req.path)
+		}
+		if !contains(req.contentType, "multipart/form-data") || !contains(string(req.body), `name="chart"`) {
+			t.Errorf("expected multipart chart upload, got %q", req.contentType)
+		}
+	}
+
+	if links[0].Name != "api 1.3.0-rc.1 (Helm, beta)" || links[0].LinkType != "package" {
+		t.Errorf("unexpected link %+v", links[0])
+	}
+	if !contains(links[1].URL, "/packages/helm/beta/charts/worker-1%2E3%2E0-rc%2E1%2Etgz") {
+		t.Errorf("unexpected link URL %q", links[1].URL)
+	}
+}
+
+func TestExpandAssetGlobs(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+
+	tmpDir := t.TempDir()
+	chdirForTest(t, tmpDir)
+	_ = os.WriteFile("a.tgz", []byte("a"), 0644)
+	_ = os.WriteFile("b.tgz", []byte("b"), 0644)
+
+	got := expandAssetGlobs([]Asset{
+		{Path: "*.tgz", Registry: "helm"},
+		{Path: "plain.txt"},
+		{Path: "*.zip"},
+	})
+
+	want := []string{"a.tgz", "b.tgz", "plain.txt", "*.zip"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d assets, got %+v", len(want), got)
+	}
+	for i, path := range want {
+		if got[i].Path != path {
+			t.Errorf("asset %d: expected %q, got %q", i, path, got[i].Path)
+		}
+	}
+	if got[1].Registry != "helm" || got[2].Registry != "" {
+		t.Errorf("expected asset options to be preserved, got %+v", got)
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// Backport policies for releases that would otherwise become the latest
+// release although a higher version exists.
+const (
+	// backportsWarn publishes as configured and reports a warning.
+	backportsWarn = "warn"
+	// backportsAdjust moves released_at just before the current latest release.
+	backportsAdjust = "adjust"
+)
+
+// validBackportPolicies lists the supported backports values.
+var validBackportPolicies = map[string]bool{
+	backportsWarn:   true,
+	backportsAdjust: true,
+}
+
+// latestPermalinkPath is the path, relative to the project URL, that always
+// points to the latest release.
+const latestPermalinkPath = "/-/releases/permalink/latest"
+
+// assetPermalink is a release asset's direct download URL, reported in Outputs.
+type assetPermalink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// LatestURL keeps pointing to the asset of the latest release; it is only
+	// set while this release is the latest.
+	LatestURL string `json:"latest_url,omitempty"`
+}
+
+// parseReleasedAt parses a released_at value, returning nil when it is empty.
+func parseReleasedAt(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("released_at must be an ISO 8601 timestamp such as 2024-01-02T15:04:05Z: %w", err)
+	}
+	return &t, nil
+}
+
+// latestRelease returns the release the project's latest permalink resolves
+// to, or nil if the project has no releases.
+func latestRelease(ctx context.Context, client *gitlab.Client, projectID string) (*gitlab.Release, error) {
+	path := fmt.Sprintf("projects/%s/releases/permalink/latest", gitlab.PathEscape(projectID))
+	req, err := client.NewRequest(http.MethodGet, path, nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+
+	release := new(gitlab.Release)
+	if _, err := client.Do(req, release); err != nil {
+		if errors.Is(err, gitlab.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get latest release: %w", err)
+	}
+	return release, nil
+}
+
+// tagVersion splits a tag such as sdk/v1.3.0 into its path prefix and
+// semantic version.
+func tagVersion(tagName string) (string, *semanticVersion) {
+	prefix, version := "", tagName
+	if i := strings.LastIndex(tagName, "/"); i >= 0 {
+		prefix, version = tagName[:i], tagName[i+1:]
+	}
+	v, err := parseSemver(version)
+	if err != nil {
+		return prefix, nil
+	}
+	return prefix, v
+}
+
+// isBackport reports whether tagName is a lower version of the same tag
+// series as latest, e.g. v1.8.5 after v2.0.0.
+func isBackport(tagName string, latest *gitlab.Release) bool {
+	if latest == nil || latest.TagName == tagName {
+		return false
+	}
+	prefix, version := tagVersion(tagName)
+	latestPrefix, latestVersion := tagVersion(latest.TagName)
+	return version != nil && latestVersion != nil && prefix == latestPrefix && version.Compare(latestVersion) < 0
+}
+
+// newerRelease returns the tag of a release with a higher version in the same
+// tag series as tagName, or "" if tagName is the newest. Only the 100 most
+// recently released releases are considered.
+func newerRelease(ctx context.Context, client *gitlab.Client, projectID, tagName string) (string, error) {
+	releases, _, err := client.Releases.ListReleases(projectID, &gitlab.ListReleasesOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 100},
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed to list releases: %w", err)
+	}
+	for _, release := range releases {
+		if isBackport(tagName, release) {
+			return release.TagName, nil
+		}
+	}
+	return "", nil
+}
+
+// protectLatest decides the released_at of a new release so that backports
+// do not take over the latest permalink. It returns the released_at to use,
+// whether the release is a backport, and a warning if latest will move to it.
+func protectLatest(ctx context.Context, client *gitlab.Client, cfg *Config, projectID, tagName string, releasedAt *time.Time, now time.Time) (*time.Time, bool, string, error) {
+	latest, err := latestRelease(ctx, client, projectID)
+	if err != nil {
+		return nil, false, "", err
+	}
+	if !isBackport(tagName, latest) || latest.ReleasedAt == nil {
+		return releasedAt, false, "", nil
+	}
+
+	effective := now
+	if releasedAt != nil {
+		effective = *releasedAt
+	}
+	if effective.Before(*latest.ReleasedAt) {
+		return releasedAt, true, "", nil
+	}
+
+	if cfg.Backports == backportsAdjust {
+		adjusted := latest.ReleasedAt.Add(-time.Second)
+		return &adjusted, true, "", nil
+	}
+	return releasedAt, true, fmt.Sprintf("backport %s will become the latest release instead of %s (set backports: adjust or an earlier released_at)", tagName, latest.TagName), nil
+}
+
+// latestOutputs verifies where the latest permalink resolves after the release
+// was created and lists the permalinks of the release's direct asset links.
+func latestOutputs(ctx context.Context, client *gitlab.Client, projectID string, release *gitlab.Release, releaseURL string, links []releaseLink, notLatest bool) (map[string]any, []string, error) {
+	latest, err := latestRelease(ctx, client, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	isLatest := latest != nil && latest.TagName == release.TagName
+
+	projectURL := releaseURL
+	if i := strings.Index(releaseURL, "/-/releases/"); i >= 0 {
+		projectURL = releaseURL[:i]
+	}
+
+	permalinks := []assetPermalink{}
+	for _, link := range links {
+		if link.DirectAssetURL == "" || link.DirectAssetURL == link.URL {
+			continue
+		}
+		permalink := assetPermalink{Name: link.Name, URL: link.DirectAssetURL}
+		if i := strings.Index(link.DirectAssetURL, "/downloads/"); isLatest && i >= 0 {
+			permalink.LatestURL = projectURL + latestPermalinkPath + link.DirectAssetURL[i:]
+		}
+		permalinks = append(permalinks, permalink)
+	}
+
+	outputs := map[string]any{
+		"is_latest":        isLatest,
+		"upcoming_release": release.UpcomingRelease,
+		"latest_url":       projectURL + latestPermalinkPath,
+		"asset_permalinks": permalinks,
+	}
+	if latest != nil {
+		outputs["latest_release"] = latest.TagName
+	}
+
+	// Backports and prereleases are expected not to be the latest release;
+	// protectLatest already warned if a backport is
+	var warnings []string
+	if !isLatest && !notLatest && !release.UpcomingRelease && latest != nil {
+		warnings = append(warnings, fmt.Sprintf("latest release permalink resolves to %s, not %s", latest.TagName, release.TagName))
+	}
+	return outputs, warnings, nil
+}
+
+// withWarnings appends warnings to a successful response's message and
+// adds them to the warnings recorded in its outputs.
+func withWarnings(resp *plugin.ExecuteResponse, warnings []string) *plugin.ExecuteResponse {
+	if len(warnings) == 0 {
+		return resp
+	}
+	resp.Message += " (warning: " + strings.Join(warnings, "; ") + ")"
+	if resp.Outputs == nil {
+		resp.Outputs = map[string]any{}
+	}
+	existing, _ := resp.Outputs["warnings"].([]string)
+	resp.Outputs["warnings"] = append(existing, warnings...)
+	return resp
+}
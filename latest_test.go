@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestIsBackport(t *testing.T) {
+	tests := []struct {
+		tag    string
+		latest string
+		want   bool
+	}{
+		{tag: "v1.8.5", latest: "v2.0.0", want: true},
+		{tag: "v2.0.1", latest: "v2.0.0", want: false},
+		{tag: "v2.0.0", latest: "v2.0.0", want: false},
+		{tag: "v2.0.0-rc.1", latest: "v2.0.0", want: true},
+		{tag: "sdk/v1.2.0", latest: "sdk/v1.3.0", want: true},
+		{tag: "sdk/v1.2.0", latest: "cli/v2.0.0", want: false},
+		{tag: "nightly", latest: "v2.0.0", want: false},
+		{tag: "v1.0.0", latest: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag+" after "+tt.latest, func(t *testing.T) {
+			var latest *gitlab.Release
+			if tt.latest != "" {
+				latest = &gitlab.Release{TagName: tt.latest}
+			}
+			if got := isBackport(tt.tag, latest); got != tt.want {
+				t.Errorf("isBackport(%q, %q) = %v, want %v", tt.tag, tt.latest, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProtectLatest(t *testing.T) {
+	latestAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	now := latestAt.Add(48 * time.Hour)
+	earlier := latestAt.Add(-24 * time.Hour)
+
+	tests := []struct {
+		name           string
+		latest         string
+		tag            string
+		policy         string
+		releasedAt     *time.Time
+		wantReleasedAt *time.Time
+		wantBackport   bool
+		wantWarning    bool
+	}{
+		{name: "no releases yet", tag: "v1.0.0"},
+		{name: "newer version", latest: "v2.0.0", tag: "v2.1.0"},
+		{name: "backport warns", latest: "v2.0.0", tag: "v1.8.5", wantBackport: true, wantWarning: true},
+		{
+			name:           "backport adjusts released_at",
+			latest:         "v2.0.0",
+			tag:            "v1.8.5",
+			policy:         "adjust",
+			wantReleasedAt: gitlab.Ptr(latestAt.Add(-time.Second)),
+			wantBackport:   true,
+		},
+		{
+			name:           "backport already released earlier",
+			latest:         "v2.0.0",
+			tag:            "v1.8.5",
+			releasedAt:     &earlier,
+			wantReleasedAt: &earlier,
+			wantBackport:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if !contains(r.URL.Path, "/releases/permalink/latest") || tt.latest == "" {
+					http.NotFound(w, r)
+					return
+				}
+				_ = json.NewEncoder(w).Encode(gitlab.Release{TagName: tt.latest, ReleasedAt: &latestAt})
+			})
+			client, err := gitlab.NewClient("glpat-test", gitlab.WithBaseURL(server.URL+"/api/v4/"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			releasedAt, backport, warning, err := protectLatest(context.Background(), client, &Config{Backports: tt.policy}, "acme/app", tt.tag, tt.releasedAt, now)
+			if err != nil {
+				t.Fatalf("protectLatest returned error: %v", err)
+			}
+			if backport != tt.wantBackport || (warning != "") != tt.wantWarning {
+				t.Errorf("got backport %v warning %q, want backport %v warning %v", backport, warning, tt.wantBackport, tt.wantWarning)
+			}
+			if (releasedAt == nil) != (tt.wantReleasedAt == nil) || (releasedAt != nil && !releasedAt.Equal(*tt.wantReleasedAt)) {
+				t.Errorf("got released_at %v, want %v", releasedAt, tt.wantReleasedAt)
+			}
+		})
+	}
+}
+
+func TestExecuteCheckLatest(t *testing.T) {
+	latestAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		tag            string
+		config         map[string]any
+		latestBefore   string
+		latestAfter    string
+		wantIsLatest   bool
+		wantReleasedAt string
+		wantWarning    string
+	}{
+		{
+			name:         "new release becomes latest",
+			tag:          "v2.1.0",
+			config:       map[string]any{},
+			latestBefore: "v2.0.0",
+			latestAfter:  "v2.1.0",
+			wantIsLatest: true,
+		},
+		{
+			name:           "backport keeps latest",
+			tag:            "v1.8.5",
+			config:         map[string]any{"backports": "adjust"},
+			latestBefore:   "v2.0.0",
+			latestAfter:    "v2.0.0",
+			wantReleasedAt: "2024-06-01T11:59:59Z",
+		},
+		{
+			name:           "configured released_at keeps an older release latest",
+			tag:            "v2.1.0",
+			config:         map[string]any{"released_at": "2024-01-01T00:00:00Z"},
+			latestBefore:   "v2.0.0",
+			latestAfter:    "v2.0.0",
+			wantReleasedAt: "2024-01-01T00:00:00Z",
+			wantWarning:    "latest release permalink resolves to v2.0.0, not v2.1.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created map[string]any
+			server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case contains(r.URL.Path, "/releases/permalink/latest"):
+					tag := tt.latestBefore
+					if created != nil {
+						tag = tt.latestAfter
+					}
+					_ = json.NewEncoder(w).Encode(gitlab.Release{TagName: tag, ReleasedAt: &latestAt})
+				case r.Method == http.MethodGet && contains(r.URL.Path, "/assets/links"):
+					_, _ = w.Write([]byte(`[
+						{"id": 1, "name": "app.tar.gz", "url": "https://gitlab.example.com/api/v4/projects/1/packages/generic/release-assets/` + tt.tag + `/app.tar.gz", "direct_asset_url": "https://gitlab.example.com/acme/app/-/releases/` + tt.tag + `/downloads/bin/app.tar.gz"},
+						{"id": 2, "name": "Docs", "url": "https://docs.example.com", "direct_asset_url": "https://docs.example.com"}
+					]`))
+				case r.Method == http.MethodPost && contains(r.URL.Path, "/releases"):
+					_ = json.NewDecoder(r.Body).Decode(&created)
+					w.WriteHeader(http.StatusCreated)
+					_, _ = w.Write([]byte(`{"tag_name": "` + tt.tag + `", "_links": {"self": "https://gitlab.example.com/acme/app/-/releases/` + tt.tag + `"}}`))
+				default:
+					http.NotFound(w, r)
+				}
+			})
+
+			config := map[string]any{
+				"token":        "glpat-test",
+				"base_url":     server.URL,
+				"project_id":   "acme/app",
+				"check_latest": true,
+			}
+			for key, value := range tt.config {
+				config[key] = value
+			}
+
+			p := &GitLabPlugin{}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook:    plugin.HookPostPublish,
+				Config:  config,
+				Context: plugin.ReleaseContext{TagName: tt.tag},
+			})
+			if err != nil {
+				t.Fatalf("Execute returned error: %v", err)
+			}
+			if !resp.Success {
+				t.Fatalf("expected success, got %+v", resp)
+			}
+
+			if got, _ := created["released_at"].(string); got != tt.wantReleasedAt {
+				t.Errorf("expected released_at %q, got %q", tt.wantReleasedAt, got)
+			}
+			if resp.Outputs["is_latest"] != tt.wantIsLatest || resp.Outputs["latest_release"] != tt.latestAfter {
+				t.Errorf("unexpected latest outputs %v", resp.Outputs)
+			}
+			if resp.Outputs["latest_url"] != "https://gitlab.example.com/acme/app/-/releases/permalink/latest" {
+				t.Errorf("unexpected latest_url %v", resp.Outputs["latest_url"])
+			}
+
+			permalinks := resp.Outputs["asset_permalinks"].([]assetPermalink)
+			if len(permalinks) != 1 || permalinks[0].URL != "https://gitlab.example.com/acme/app/-/releases/"+tt.tag+"/downloads/bin/app.tar.gz" {
+				t.Fatalf("unexpected permalinks %+v", permalinks)
+			}
+			wantLatestURL := ""
+			if tt.wantIsLatest {
+				wantLatestURL = "https://gitlab.example.com/acme/app/-/releases/permalink/latest/downloads/bin/app.tar.gz"
+			}
+			if permalinks[0].LatestURL != wantLatestURL {
+				t.Errorf("expected latest asset URL %q, got %q", wantLatestURL, permalinks[0].LatestURL)
+			}
+
+			if tt.wantWarning == "" && resp.Outputs["warnings"] != nil {
+				t.Errorf("unexpected warnings %v", resp.Outputs["warnings"])
+			}
+			if tt.wantWarning != "" && !contains(resp.Message, tt.wantWarning) {
+				t.Errorf("expected warning %q in message %q", tt.wantWarning, resp.Message)
+			}
+		})
+	}
+}
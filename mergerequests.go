@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// mergeRequestViolation describes a merged MR that fails the approval or
+// discussion requirements.
+type mergeRequestViolation struct {
+	IID     int64    `json:"iid"`
+	Title   string   `json:"title"`
+	WebURL  string   `json:"web_url"`
+	Reasons []string `json:"reasons"`
+}
+
+// String describes the violation for messages.
+func (v mergeRequestViolation) String() string {
+	return fmt.Sprintf("!%d %s (%s)", v.IID, v.Title, strings.Join(v.Reasons, ", "))
+}
+
+// releaseRange returns the refs bounding the release: the previous version's
+// tag and the release commit. from is empty for a first release.
+func releaseRange(releaseCtx plugin.ReleaseContext) (from, to string) {
+	if releaseCtx.PreviousVersion != "" {
+		// Reuse the tag prefix of the new tag (e.g. "v" or "api/v")
+		prefix := "v"
+		if releaseCtx.Version != "" && strings.HasSuffix(releaseCtx.TagName, releaseCtx.Version) {
+			prefix = strings.TrimSuffix(releaseCtx.TagName, releaseCtx.Version)
+		}
+		from = prefix + strings.TrimPrefix(releaseCtx.PreviousVersion, "v")
+	}
+
+	// The release tag usually does not exist yet before approval
+	switch {
+	case releaseCtx.CommitSHA != "":
+		to = releaseCtx.CommitSHA
+	case releaseCtx.Branch != "":
+		to = releaseCtx.Branch
+	default:
+		to = releaseCtx.TagName
+	}
+	return from, to
+}
+
+// mergedRequestsInRange returns the MRs merged between from and to, sorted by IID.
+func mergedRequestsInRange(ctx context.Context, client *gitlab.Client, projectID, from, to string) ([]*gitlab.BasicMergeRequest, error) {
+	compare, _, err := client.Repositories.Compare(projectID, &gitlab.CompareOptions{
+		From: gitlab.Ptr(from),
+		To:   gitlab.Ptr(to),
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %s..%s: %w", from, to, err)
+	}
+
+	seen := map[int64]*gitlab.BasicMergeRequest{}
+	for _, commit := range compare.Commits {
+		mrs, _, err := client.Commits.ListMergeRequestsByCommit(projectID, commit.ID, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list merge requests for %s: %w", commit.ShortID, err)
+		}
+		for _, mr := range mrs {
+			if mr.State == "merged" {
+				seen[mr.IID] = mr
+			}
+		}
+	}
+
+	merged := make([]*gitlab.BasicMergeRequest, 0, len(seen))
+	for _, mr := range seen {
+		merged = append(merged, mr)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].IID < merged[j].IID })
+	return merged, nil
+}
+
+// hasUnresolvedThreads reports whether an MR has a resolvable thread that is
+// not resolved. The MR's blocking_discussions_resolved cannot be used, as it
+// is always true in projects that do not require threads to be resolved.
+func hasUnresolvedThreads(ctx context.Context, client *gitlab.Client, projectID string, iid int64) (bool, error) {
+	opts := &gitlab.ListMergeRequestDiscussionsOptions{ListOptions: gitlab.ListOptions{PerPage: 100}}
+	for {
+		discussions, resp, err := client.Discussions.ListMergeRequestDiscussions(projectID, iid, opts, gitlab.WithContext(ctx))
+		if err != nil {
+			return false, fmt.Errorf("failed to list threads of !%d: %w", iid, err)
+		}
+		for _, discussion := range discussions {
+			for _, note := range discussion.Notes {
+				if note.Resolvable && !note.Resolved {
+					return true, nil
+				}
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			return false, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// checkMergeRequests blocks approval when an MR merged since the previous
+// version lacked its required approvals or had unresolved threads.
+func (p *GitLabPlugin) checkMergeRequests(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext) (*plugin.ExecuteResponse, error) {
+	client, err := p.getClient(cfg)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to create GitLab client: %v", err),
+		}, nil
+	}
+
+	projectID := resolveProjectID(cfg, releaseCtx)
+	if projectID == "" {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   "project_id is required (set in config or provide repository owner/name)",
+		}, nil
+	}
+
+	from, to := releaseRange(releaseCtx)
+	if from == "" {
+		return &plugin.ExecuteResponse{
+			Success: true,
+			Message: "No previous version; merge request checks skipped",
+		}, nil
+	}
+
+	mrs, err := mergedRequestsInRange(ctx, client, projectID, from, to)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	violations := []mergeRequestViolation{}
+	for _, mr := range mrs {
+		var reasons []string
+
+		approvals, _, err := client.MergeRequestApprovals.GetConfiguration(projectID, mr.IID, gitlab.WithContext(ctx))
+		if err != nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   fmt.Sprintf("failed to get approvals for !%d: %v", mr.IID, err),
+			}, nil
+		}
+		if approvals.ApprovalsLeft > 0 {
+			reasons = append(reasons, fmt.Sprintf("%d of %d required approvals missing", approvals.ApprovalsLeft, approvals.ApprovalsRequired))
+		}
+		unresolved, err := hasUnresolvedThreads(ctx, client, projectID, mr.IID)
+		if err != nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   err.Error(),
+			}, nil
+		}
+		if unresolved {
+			reasons = append(reasons, "unresolved threads")
+		}
+
+		if len(reasons) > 0 {
+			violations = append(violations, mergeRequestViolation{
+				IID:     mr.IID,
+				Title:   mr.Title,
+				WebURL:  mr.WebURL,
+				Reasons: reasons,
+			})
+		}
+	}
+
+	outputs := map[string]any{
+		"merge_requests_checked": len(mrs),
+		"violations":             violations,
+	}
+	if len(violations) > 0 {
+		descriptions := make([]string, len(violations))
+		for i, v := range violations {
+			descriptions[i] = v.String()
+		}
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("%d of %d merge requests in %s..%s do not meet review requirements: %s", len(violations), len(mrs), from, to, strings.Join(descriptions, "; ")),
+			Outputs: outputs,
+		}, nil
+	}
+
+	return &plugin.ExecuteResponse{
+		Success: true,
+		Message: fmt.Sprintf("All %d merge requests in %s..%s are approved with resolved threads", len(mrs), from, to),
+		Outputs: outputs,
+	}, nil
+}
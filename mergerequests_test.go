@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestReleaseRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		releaseCtx plugin.ReleaseContext
+		wantFrom   string
+		wantTo     string
+	}{
+		{
+			name:       "commit range",
+			releaseCtx: plugin.ReleaseContext{Version: "1.3.0", PreviousVersion: "1.2.0", TagName: "v1.3.0", CommitSHA: "abc123"},
+			wantFrom:   "v1.2.0",
+			wantTo:     "abc123",
+		},
+		{
+			name:       "component tag prefix",
+			releaseCtx: plugin.ReleaseContext{Version: "2.0.0", PreviousVersion: "1.9.0", TagName: "api/v2.0.0", Branch: "main"},
+			wantFrom:   "api/v1.9.0",
+			wantTo:     "main",
+		},
+		{
+			name:       "first release",
+			releaseCtx: plugin.ReleaseContext{Version: "1.0.0", TagName: "v1.0.0"},
+			wantTo:     "v1.0.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := releaseRange(tt.releaseCtx)
+			if from != tt.wantFrom || to != tt.wantTo {
+				t.Errorf("releaseRange() = %q..%q, want %q..%q", from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
+
+// mergeRequestServer serves a release range of three commits touching MRs !1
+// (approved), !2 (missing an approval) and !3 (unresolved threads). As in
+// projects that do not require resolved threads, every MR reports its
+// blocking discussions as resolved.
+func mergeRequestServer(t *testing.T) string {
+	t.Helper()
+	mrs := map[string][]gitlab.BasicMergeRequest{
+		"c1": {{IID: 1, Title: "Add feature", State: "merged", BlockingDiscussionsResolved: true}},
+		"c2": {{IID: 1, Title: "Add feature", State: "merged", BlockingDiscussionsResolved: true}, {IID: 4, Title: "Draft", State: "closed"}},
+		"c3": {{IID: 2, Title: "Fix bug", State: "merged", BlockingDiscussionsResolved: true, WebURL: "https://gitlab.example.com/mr/2"}},
+		"c4": {{IID: 3, Title: "Refactor", State: "merged", BlockingDiscussionsResolved: true}},
+	}
+	discussions := map[string][]gitlab.Discussion{
+		"1": {
+			{Notes: []*gitlab.Note{{Body: "LGTM"}}},
+			{Notes: []*gitlab.Note{{Body: "Typo", Resolvable: true, Resolved: true}}},
+		},
+		"3": {
+			{Notes: []*gitlab.Note{{Body: "Missing test", Resolvable: true}, {Body: "Will add", Resolvable: true}}},
+		},
+	}
+	approvals := map[string]gitlab.MergeRequestApprovals{
+		"1": {ApprovalsRequired: 2, ApprovalsLeft: 0},
+		"2": {ApprovalsRequired: 2, ApprovalsLeft: 1},
+		"3": {ApprovalsRequired: 1, ApprovalsLeft: 0},
+	}
+
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		const prefix = "/api/v4/projects/group/project/"
+		path := r.URL.Path
+		switch {
+		case path == prefix+"repository/compare":
+			if r.URL.Query().Get("from") != "v1.2.0" || r.URL.Query().Get("to") != "abc123" {
+				t.Errorf("unexpected compare range %s", r.URL.RawQuery)
+			}
+			_ = json.NewEncoder(w).Encode(gitlab.Compare{Commits: []*gitlab.Commit{{ID: "c1"}, {ID: "c2"}, {ID: "c3"}, {ID: "c4"}}})
+		case contains(path, "/repository/commits/"):
+			var sha string
+			for id := range mrs {
+				if path == prefix+"repository/commits/"+id+"/merge_requests" {
+					sha = id
+				}
+			}
+			_ = json.NewEncoder(w).Encode(mrs[sha])
+		case contains(path, "/merge_requests/"):
+			for iid, a := range approvals {
+				switch path {
+				case prefix + "merge_requests/" + iid + "/approvals":
+					_ = json.NewEncoder(w).Encode(a)
+					return
+				case prefix + "merge_requests/" + iid + "/discussions":
+					_ = json.NewEncoder(w).Encode(append([]gitlab.Discussion{}, discussions[iid]...))
+					return
+				}
+			}
+			http.NotFound(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	return server.URL
+}
+
+func TestCheckMergeRequests(t *testing.T) {
+	serverURL := mergeRequestServer(t)
+	p := &GitLabPlugin{}
+	cfg := &Config{Token: "glpat-test", BaseURL: serverURL, ProjectID: "group/project", CheckMergeRequests: true}
+	releaseCtx := plugin.ReleaseContext{Version: "1.3.0", PreviousVersion: "1.2.0", TagName: "v1.3.0", CommitSHA: "abc123"}
+
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook:    plugin.HookPreApprove,
+		Config:  map[string]any{"token": "glpat-test", "base_url": serverURL, "project_id": "group/project", "check_merge_requests": true},
+		Context: releaseCtx,
+	})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if resp.Success {
+		t.Fatalf("expected blocking response, got %+v", resp)
+	}
+	for _, want := range []string{"2 of 3 merge requests in v1.2.0..abc123", "!2 Fix bug (1 of 2 required approvals missing)", "!3 Refactor (unresolved threads)"} {
+		if !contains(resp.Error, want) {
+			t.Errorf("expected %q in %q", want, resp.Error)
+		}
+	}
+	violations, _ := resp.Outputs["violations"].([]mergeRequestViolation)
+	if len(violations) != 2 || violations[0].WebURL != "https://gitlab.example.com/mr/2" {
+		t.Errorf("unexpected violations output %+v", resp.Outputs["violations"])
+	}
+	if resp.Outputs["merge_requests_checked"] != 3 {
+		t.Errorf("expected 3 merged MRs checked, got %v", resp.Outputs["merge_requests_checked"])
+	}
+
+	// First releases have no range to check
+	resp, err = p.checkMergeRequests(context.Background(), cfg, plugin.ReleaseContext{Version: "1.0.0", TagName: "v1.0.0"})
+	if err != nil || !resp.Success || !contains(resp.Message, "skipped") {
+		t.Errorf("expected skipped check, got %+v (%v)", resp, err)
+	}
+}
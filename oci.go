@@ -0,0 +1,768 @@
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// registryContainer is the asset registry value for container images.
+const registryContainer = "container"
+
+// OCI media types used when pushing images.
+const (
+	mediaTypeOCIManifest  = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeOCIIndex     = "application/vnd.oci.image.index.v1+json"
+	mediaTypeOCIConfig    = "application/vnd.oci.image.config.v1+json"
+	mediaTypeOCILayer     = "application/vnd.oci.image.layer.v1.tar"
+	mediaTypeOCILayerGzip = "application/vnd.oci.image.layer.v1.tar+gzip"
+	mediaTypeDockerList   = "application/vnd.docker.distribution.manifest.list.v2+json"
+)
+
+// registryUsername is sent with the GitLab token when authenticating to the
+// container registry. GitLab accepts any username for personal access tokens.
+const registryUsername = "oauth2"
+
+// ociDescriptor references content by digest.
+type ociDescriptor struct {
+	MediaType string `json:"mediaType,omitempty"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+// ociManifestDocument covers the fields of image manifests and indexes the plugin reads.
+type ociManifestDocument struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType,omitempty"`
+	Config        *ociDescriptor  `json:"config,omitempty"`
+	Layers        []ociDescriptor `json:"layers,omitempty"`
+	Manifests     []ociDescriptor `json:"manifests,omitempty"`
+}
+
+// ociBlob is a blob stored in a local file.
+type ociBlob struct {
+	digest string
+	size   int64
+	path   string
+}
+
+// ociManifest is a manifest or index ready to be pushed, with the blobs and
+// child manifests it references.
+type ociManifest struct {
+	mediaType string
+	digest    string
+	content   []byte
+	blobs     []ociBlob
+	children  []*ociManifest
+}
+
+// publishContainerImage pushes an OCI layout directory or docker-archive
+// tarball to the project's container registry, tagged from the release version.
+func (p *GitLabPlugin) publishContainerImage(ctx context.Context, client *gitlab.Client, cfg *Config, projectID string, releaseCtx plugin.ReleaseContext, asset Asset) (*publishedAsset, error) {
+	// Validate and sanitize the asset path to prevent path traversal
+	validatedPath, err := validateAssetPath(asset.Path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asset path %s: %w", asset.Path, err)
+	}
+	info, err := os.Lstat(validatedPath)
+	if err != nil {
+		return nil, fmt.Errorf("asset file not accessible %s: %w", asset.Path, err)
+	}
+
+	var image *ociManifest
+	if info.IsDir() {
+		image, err = loadOCILayout(validatedPath)
+	} else {
+		var cleanup func()
+		image, cleanup, err = loadDockerArchive(validatedPath)
+		defer cleanup()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image %s: %w", asset.Path, err)
+	}
+
+	aliases := cfg.ImageTags
+	if len(aliases) > 0 {
+		// Aliases of an older version would move them back to older code
+		newer, err := newerRelease(ctx, client, projectID, releaseCtx.TagName)
+		if err != nil {
+			return nil, err
+		}
+		if newer != "" {
+			aliases = nil
+		}
+	}
+	tags, err := imageTags(releaseCtx.Version, aliases)
+	if err != nil {
+		return nil, err
+	}
+
+	registryURL, err := containerRegistryURL(cfg)
+	if err != nil {
+		return nil, err
+	}
+	repository, err := p.containerRepository(ctx, client, projectID, asset.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := &registryClient{
+		httpClient: http.DefaultClient,
+		baseURL:    registryURL,
+		repository: repository,
+		username:   registryUsername,
+		password:   resolveToken(cfg),
+	}
+	if err := registry.authenticate(ctx); err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		if err := registry.pushManifest(ctx, image, tag); err != nil {
+			return nil, fmt.Errorf("failed to push %s:%s: %w", repository, tag, err)
+		}
+	}
+
+	reference := fmt.Sprintf("%s/%s:%s", registryURL.Host, repository, tags[0])
+	return &publishedAsset{
+		artifacts: []plugin.Artifact{{
+			Name:     fmt.Sprintf("%s:%s", repository, tags[0]),
+			Path:     reference,
+			Type:     "container_image",
+			Size:     int64(len(image.content)),
+			Checksum: image.digest,
+		}},
+		link: &AssetLink{
+			Name:     fmt.Sprintf("%s (container image)", reference),
+			URL:      fmt.Sprintf("%s://%s", registryURL.Scheme, reference),
+			LinkType: "image",
+		},
+	}, nil
+}
+
+// Image tag aliases that can be pushed in addition to the exact version tag.
+const (
+	imageTagMinor  = "minor"
+	imageTagMajor  = "major"
+	imageTagLatest = "latest"
+)
+
+// validImageTags lists the supported image_tags values.
+var validImageTags = map[string]bool{
+	imageTagMinor:  true,
+	imageTagMajor:  true,
+	imageTagLatest: true,
+}
+
+// imageTags returns the tags to push for a release version: the version
+// itself, followed for stable versions by the requested aliases, e.g.
+// major.minor, major and latest for 1.4.2.
+func imageTags(version string, aliases []string) ([]string, error) {
+	v, err := parseSemver(version)
+	if err != nil {
+		return nil, fmt.Errorf("container images require a semantic version: %w", err)
+	}
+
+	// Docker tags cannot contain "+", so build metadata is joined with "-"
+	tags := []string{strings.ReplaceAll(strings.TrimPrefix(strings.TrimSpace(version), "v"), "+", "-")}
+	if v.IsPrerelease() {
+		return tags, nil
+	}
+	for _, alias := range []string{imageTagMinor, imageTagMajor, imageTagLatest} {
+		if !slices.Contains(aliases, alias) {
+			continue
+		}
+		switch alias {
+		case imageTagMinor:
+			tags = append(tags, fmt.Sprintf("%d.%d", v.Major, v.Minor))
+		case imageTagMajor:
+			tags = append(tags, fmt.Sprintf("%d", v.Major))
+		case imageTagLatest:
+			tags = append(tags, "latest")
+		}
+	}
+	return tags, nil
+}
+
+// containerRegistryURL returns the configured registry URL, defaulting to
+// registry.<host> of the GitLab instance.
+func containerRegistryURL(cfg *Config) (*url.URL, error) {
+	raw := cfg.ContainerRegistry
+	if raw == "" {
+		baseURL := cfg.BaseURL
+		if baseURL == "" {
+			baseURL = "https://gitlab.com"
+		}
+		base, err := url.Parse(baseURL)
+		if err != nil || base.Hostname() == "" {
+			return nil, fmt.Errorf("cannot derive container registry from base_url %q", baseURL)
+		}
+		raw = "https://registry." + base.Hostname()
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(strings.TrimSuffix(raw, "/"))
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid container_registry %q", cfg.ContainerRegistry)
+	}
+	return u, nil
+}
+
+// containerRepository returns the image repository for the project. Image
+// repository names are the lowercased project path.
+func (p *GitLabPlugin) containerRepository(ctx context.Context, client *gitlab.Client, projectID, image string) (string, error) {
+	repository, err := projectPath(ctx, client, projectID)
+	if err != nil {
+		return "", err
+	}
+
+	repository = strings.ToLower(repository)
+	if image = strings.Trim(image, "/"); image != "" {
+		repository += "/" + strings.ToLower(image)
+	}
+	return repository, nil
+}
+
+// isNumericProjectID reports whether projectID is a numeric ID rather than a path.
+func isNumericProjectID(projectID string) bool {
+	if projectID == "" {
+		return false
+	}
+	for _, r := range projectID {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// loadOCILayout reads the image referenced by an OCI image layout's index.json.
+// A layout with several manifests is pushed as an index.
+func loadOCILayout(dir string) (*ociManifest, error) {
+	if _, err := os.Stat(filepath.Join(dir, "oci-layout")); err != nil {
+		return nil, fmt.Errorf("not an OCI image layout (missing oci-layout): %w", err)
+	}
+
+	indexData, err := os.ReadFile(filepath.Join(dir, "index.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index.json: %w", err)
+	}
+	var index ociManifestDocument
+	if err := json.Unmarshal(indexData, &index); err != nil {
+		return nil, fmt.Errorf("invalid index.json: %w", err)
+	}
+
+	switch len(index.Manifests) {
+	case 0:
+		return nil, fmt.Errorf("index.json does not reference any manifests")
+	case 1:
+		return loadLayoutManifest(dir, index.Manifests[0], 0)
+	}
+
+	root := &ociManifest{
+		mediaType: mediaTypeOCIIndex,
+		digest:    sha256Digest(indexData),
+		content:   indexData,
+	}
+	for _, desc := range index.Manifests {
+		child, err := loadLayoutManifest(dir, desc, 1)
+		if err != nil {
+			return nil, err
+		}
+		root.children = append(root.children, child)
+	}
+	return root, nil
+}
+
+// loadLayoutManifest reads a manifest blob from an OCI layout, recursing into indexes.
+func loadLayoutManifest(dir string, desc ociDescriptor, depth int) (*ociManifest, error) {
+	if depth > 4 {
+		return nil, fmt.Errorf("image index nesting too deep")
+	}
+
+	path, err := layoutBlobPath(dir, desc.Digest)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := regularFileSize(path); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", desc.Digest, err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %w", desc.Digest, err)
+	}
+	if digest := sha256Digest(content); digest != desc.Digest {
+		return nil, fmt.Errorf("manifest digest mismatch: expected %s, got %s", desc.Digest, digest)
+	}
+
+	var doc ociManifestDocument
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", desc.Digest, err)
+	}
+
+	mediaType := desc.MediaType
+	if mediaType == "" {
+		mediaType = doc.MediaType
+	}
+	m := &ociManifest{mediaType: mediaType, digest: desc.Digest, content: content}
+
+	if mediaType == mediaTypeOCIIndex || mediaType == mediaTypeDockerList {
+		for _, child := range doc.Manifests {
+			childManifest, err := loadLayoutManifest(dir, child, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			m.children = append(m.children, childManifest)
+		}
+		return m, nil
+	}
+
+	if doc.Config == nil {
+		return nil, fmt.Errorf("manifest %s has no config", desc.Digest)
+	}
+	for _, blob := range append([]ociDescriptor{*doc.Config}, doc.Layers...) {
+		blobPath, err := layoutBlobPath(dir, blob.Digest)
+		if err != nil {
+			return nil, err
+		}
+		size, err := regularFileSize(blobPath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blob %s: %w", blob.Digest, err)
+		}
+		m.blobs = append(m.blobs, ociBlob{digest: blob.Digest, size: size, path: blobPath})
+	}
+	return m, nil
+}
+
+// digestPattern matches content digests the plugin can resolve on disk.
+var digestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// layoutBlobPath returns the location of a blob inside an OCI layout.
+func layoutBlobPath(dir, digest string) (string, error) {
+	if !digestPattern.MatchString(digest) {
+		return "", fmt.Errorf("unsupported digest %q", digest)
+	}
+	return filepath.Join(dir, "blobs", "sha256", strings.TrimPrefix(digest, "sha256:")), nil
+}
+
+// regularFileSize returns the size of path, rejecting symlinks and other
+// non-regular files so that layout blobs cannot point outside the layout.
+func regularFileSize(path string) (int64, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return 0, err
+	}
+	if !info.Mode().IsRegular() {
+		return 0, fmt.Errorf("not a regular file: %s", filepath.Base(path))
+	}
+	return info.Size(), nil
+}
+
+// dockerArchiveManifest is an entry of a docker-archive manifest.json.
+type dockerArchiveManifest struct {
+	Config   string   `json:"Config"`
+	RepoTags []string `json:"RepoTags"`
+	Layers   []string `json:"Layers"`
+}
+
+// loadDockerArchive extracts a `docker save` tarball and builds an OCI
+// manifest for its single image. The cleanup function removes the extracted files.
+func loadDockerArchive(path string) (*ociManifest, func(), error) {
+	tmpDir, err := os.MkdirTemp("", "relicta-gitlab-image-")
+	if err != nil {
+		return nil, func() {}, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	cleanup := func() { _ = os.RemoveAll(tmpDir) }
+
+	if err := extractTarball(path, tmpDir); err != nil {
+		cleanup()
+		return nil, func() {}, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "manifest.json"))
+	if err != nil {
+		cleanup()
+		return nil, func() {}, fmt.Errorf("not a docker archive (missing manifest.json): %w", err)
+	}
+	var entries []dockerArchiveManifest
+	if err := json.Unmarshal(data, &entries); err != nil {
+		cleanup()
+		return nil, func() {}, fmt.Errorf("invalid manifest.json: %w", err)
+	}
+	if len(entries) != 1 {
+		cleanup()
+		return nil, func() {}, fmt.Errorf("docker archive must contain exactly one image, found %d", len(entries))
+	}
+
+	m, err := buildArchiveManifest(tmpDir, entries[0])
+	if err != nil {
+		cleanup()
+		return nil, func() {}, err
+	}
+	return m, cleanup, nil
+}
+
+// buildArchiveManifest creates an OCI manifest for an extracted docker-archive image.
+func buildArchiveManifest(dir string, entry dockerArchiveManifest) (*ociManifest, error) {
+	configBlob, err := describeFile(dir, entry.Config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image config: %w", err)
+	}
+
+	doc := ociManifestDocument{
+		SchemaVersion: 2,
+		MediaType:     mediaTypeOCIManifest,
+		Config: &ociDescriptor{
+			MediaType: mediaTypeOCIConfig,
+			Digest:    configBlob.digest,
+			Size:      configBlob.size,
+		},
+	}
+	blobs := []ociBlob{*configBlob}
+
+	for _, layer := range entry.Layers {
+		layerBlob, err := describeFile(dir, layer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid layer: %w", err)
+		}
+		mediaType := mediaTypeOCILayer
+		if gzipped, err := isGzipFile(layerBlob.path); err != nil {
+			return nil, err
+		} else if gzipped {
+			mediaType = mediaTypeOCILayerGzip
+		}
+		doc.Layers = append(doc.Layers, ociDescriptor{
+			MediaType: mediaType,
+			Digest:    layerBlob.digest,
+			Size:      layerBlob.size,
+		})
+		blobs = append(blobs, *layerBlob)
+	}
+
+	content, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return &ociManifest{
+		mediaType: mediaTypeOCIManifest,
+		digest:    sha256Digest(content),
+		content:   content,
+		blobs:     blobs,
+	}, nil
+}
+
+// describeFile computes the digest and size of a file inside dir.
+func describeFile(dir, name string) (*ociBlob, error) {
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+		return nil, fmt.Errorf("path escapes archive: %s", name)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return nil, err
+	}
+	return &ociBlob{digest: "sha256:" + hex.EncodeToString(h.Sum(nil)), size: size, path: path}, nil
+}
+
+// isGzipFile reports whether a file starts with the gzip magic number.
+func isGzipFile(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = f.Close() }()
+
+	magic := make([]byte, 2)
+	if _, err := io.ReadFull(f, magic); err != nil {
+		return false, nil
+	}
+	return magic[0] == 0x1f && magic[1] == 0x8b, nil
+}
+
+// extractTarball extracts a (optionally gzip-compressed) tarball into dir.
+// Only regular files and directories are extracted; entries that would
+// escape dir and links of any kind are rejected.
+func extractTarball(path, dir string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	br := bufio.NewReader(f)
+	var r io.Reader = br
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tarball: %w", err)
+		}
+
+		name := filepath.Clean(filepath.FromSlash(hdr.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("path traversal not allowed in tarball: %s", hdr.Name)
+		}
+		target := filepath.Join(dir, name)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(out, tr)
+			if closeErr := out.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+		case tar.TypeSymlink, tar.TypeLink:
+			return fmt.Errorf("links not allowed in image archives: %s", hdr.Name)
+		}
+	}
+}
+
+// sha256Digest returns the OCI digest of data.
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// registryClient pushes content to an OCI distribution registry.
+type registryClient struct {
+	httpClient *http.Client
+	baseURL    *url.URL
+	repository string
+	username   string
+	password   string
+	// authorization is the Authorization header obtained by authenticate.
+	authorization string
+}
+
+// authChallengePattern matches key="value" pairs in a WWW-Authenticate header.
+var authChallengePattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// parseAuthChallenge splits a WWW-Authenticate header into its scheme and parameters.
+func parseAuthChallenge(header string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	params := make(map[string]string)
+	for _, m := range authChallengePattern.FindAllStringSubmatch(rest, -1) {
+		params[strings.ToLower(m[1])] = m[2]
+	}
+	return strings.ToLower(scheme), params
+}
+
+// authenticate pings the registry and obtains credentials for pushing to the
+// repository, following the registry's Bearer or Basic auth challenge.
+func (r *registryClient) authenticate(ctx context.Context) error {
+	resp, err := r.request(ctx, http.MethodGet, r.baseURL.String()+"/v2/", nil, 0, nil)
+	if err != nil {
+		return fmt.Errorf("container registry not reachable: %w", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("container registry returned %s", resp.Status)
+		}
+		return nil
+	}
+
+	scheme, params := parseAuthChallenge(resp.Header.Get("WWW-Authenticate"))
+	switch scheme {
+	case "basic":
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth(r.username, r.password)
+		r.authorization = req.Header.Get("Authorization")
+		return nil
+	case "bearer":
+		return r.fetchBearerToken(ctx, params)
+	default:
+		return fmt.Errorf("unsupported registry auth challenge %q", resp.Header.Get("WWW-Authenticate"))
+	}
+}
+
+// fetchBearerToken requests a push token from the registry's token service.
+func (r *registryClient) fetchBearerToken(ctx context.Context, params map[string]string) error {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return fmt.Errorf("registry auth challenge has no realm")
+	}
+	q := realm.Query()
+	if service := params["service"]; service != "" {
+		q.Set("service", service)
+	}
+	q.Set("scope", fmt.Sprintf("repository:%s:pull,push", r.repository))
+	realm.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(r.username, r.password)
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to obtain registry token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to obtain registry token: %s", resp.Status)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return fmt.Errorf("invalid registry token response: %w", err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	if token.Token == "" {
+		return fmt.Errorf("registry token response did not contain a token")
+	}
+	r.authorization = "Bearer " + token.Token
+	return nil
+}
+
+// request sends an authenticated request to the registry.
+func (r *registryClient) request(ctx context.Context, method, target string, body io.Reader, size int64, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	if r.authorization != "" {
+		req.Header.Set("Authorization", r.authorization)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return r.httpClient.Do(req)
+}
+
+// pushManifest pushes a manifest, its blobs and any child manifests, storing
+// the manifest under reference.
+func (r *registryClient) pushManifest(ctx context.Context, m *ociManifest, reference string) error {
+	for _, child := range m.children {
+		if err := r.pushManifest(ctx, child, child.digest); err != nil {
+			return err
+		}
+	}
+	for _, blob := range m.blobs {
+		if err := r.pushBlob(ctx, blob); err != nil {
+			return err
+		}
+	}
+
+	target := fmt.Sprintf("%s/v2/%s/manifests/%s", r.baseURL, r.repository, reference)
+	resp, err := r.request(ctx, http.MethodPut, target, bytes.NewReader(m.content), int64(len(m.content)), map[string]string{
+		"Content-Type": m.mediaType,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("manifest upload failed: %s", resp.Status)
+	}
+	return nil
+}
+
+// pushBlob uploads a blob unless the registry already has it.
+func (r *registryClient) pushBlob(ctx context.Context, blob ociBlob) error {
+	blobURL := fmt.Sprintf("%s/v2/%s/blobs/%s", r.baseURL, r.repository, blob.digest)
+	resp, err := r.request(ctx, http.MethodHead, blobURL, nil, 0, nil)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	uploadsURL := fmt.Sprintf("%s/v2/%s/blobs/uploads/", r.baseURL, r.repository)
+	resp, err = r.request(ctx, http.MethodPost, uploadsURL, nil, 0, nil)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("blob upload for %s not accepted: %s", blob.digest, resp.Status)
+	}
+
+	location, err := resp.Request.URL.Parse(resp.Header.Get("Location"))
+	if err != nil || resp.Header.Get("Location") == "" {
+		return fmt.Errorf("blob upload for %s returned no location", blob.digest)
+	}
+	q := location.Query()
+	q.Set("digest", blob.digest)
+	location.RawQuery = q.Encode()
+
+	f, err := os.Open(blob.path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	resp, err = r.request(ctx, http.MethodPut, location.String(), f, blob.size, map[string]string{
+		"Content-Type": "application/octet-stream",
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("blob upload for %s failed: %s", blob.digest, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,447 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// fakeRegistry is a minimal OCI distribution registry with token auth.
+type fakeRegistry struct {
+	mu        sync.Mutex
+	server    *httptest.Server
+	blobs     map[string][]byte
+	manifests map[string][]byte // "<repo>:<reference>" -> manifest
+	types     map[string]string // "<repo>:<reference>" -> content type
+	scopes    []string
+	uploads   int
+}
+
+// newFakeRegistry starts a registry that requires a Bearer token obtained
+// with the password "glpat-test".
+func newFakeRegistry(t *testing.T) *fakeRegistry {
+	t.Helper()
+	reg := &fakeRegistry{
+		blobs:     make(map[string][]byte),
+		manifests: make(map[string][]byte),
+		types:     make(map[string]string),
+	}
+	reg.server = httptest.NewServer(http.HandlerFunc(reg.handle))
+	t.Cleanup(reg.server.Close)
+	return reg
+}
+
+func (reg *fakeRegistry) handle(w http.ResponseWriter, r *http.Request) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if r.URL.Path == "/jwt/auth" {
+		if _, password, ok := r.BasicAuth(); !ok || password != "glpat-test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		reg.scopes = append(reg.scopes, r.URL.Query().Get("scope"))
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": "registry-token"})
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer registry-token" {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/jwt/auth",service="container_registry"`, reg.server.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/v2/")
+	switch {
+	case r.URL.Path == "/v2/":
+		w.WriteHeader(http.StatusOK)
+	case strings.Contains(path, "/blobs/uploads/") && r.Method == http.MethodPost:
+		reg.uploads++
+		repo := path[:strings.Index(path, "/blobs/uploads/")]
+		w.Header().Set("Location", fmt.Sprintf("/v2/%s/blobs/uploads/session-%d?state=abc", repo, reg.uploads))
+		w.WriteHeader(http.StatusAccepted)
+	case strings.Contains(path, "/blobs/uploads/") && r.Method == http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		digest := r.URL.Query().Get("digest")
+		if r.URL.Query().Get("state") != "abc" || sha256Digest(body) != digest {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		reg.blobs[digest] = body
+		w.WriteHeader(http.StatusCreated)
+	case strings.Contains(path, "/blobs/") && r.Method == http.MethodHead:
+		digest := path[strings.LastIndex(path, "/")+1:]
+		if _, ok := reg.blobs[digest]; ok {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	case strings.Contains(path, "/manifests/") && r.Method == http.MethodPut:
+		i := strings.Index(path, "/manifests/")
+		key := path[:i] + ":" + path[i+len("/manifests/"):]
+		body, _ := io.ReadAll(r.Body)
+		reg.manifests[key] = body
+		reg.types[key] = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// writeBlob stores content in an OCI layout and returns its descriptor.
+func writeBlob(t *testing.T, layout, mediaType string, content []byte) ociDescriptor {
+	t.Helper()
+	digest := sha256Digest(content)
+	dir := filepath.Join(layout, "blobs", "sha256")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("failed to create blobs dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, strings.TrimPrefix(digest, "sha256:")), content, 0644); err != nil {
+		t.Fatalf("failed to write blob: %v", err)
+	}
+	return ociDescriptor{MediaType: mediaType, Digest: digest, Size: int64(len(content))}
+}
+
+// writeOCILayout creates a single-image OCI layout at dir.
+func writeOCILayout(t *testing.T, dir string) ociDescriptor {
+	t.Helper()
+	config := writeBlob(t, dir, mediaTypeOCIConfig, []byte(`{"architecture":"amd64","os":"linux"}`))
+	layer := writeBlob(t, dir, mediaTypeOCILayerGzip, []byte("\x1f\x8blayer-bytes"))
+	manifest, _ := json.Marshal(ociManifestDocument{
+		SchemaVersion: 2,
+		MediaType:     mediaTypeOCIManifest,
+		Config:        &config,
+		Layers:        []ociDescriptor{layer},
+	})
+	manifestDesc := writeBlob(t, dir, mediaTypeOCIManifest, manifest)
+	index, _ := json.Marshal(ociManifestDocument{SchemaVersion: 2, Manifests: []ociDescriptor{manifestDesc}})
+	_ = os.WriteFile(filepath.Join(dir, "index.json"), index, 0644)
+	_ = os.WriteFile(filepath.Join(dir, "oci-layout"), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0644)
+	return manifestDesc
+}
+
+// writeDockerArchive creates a `docker save` style tarball at path.
+func writeDockerArchive(t *testing.T, path string, extra map[string]string) {
+	t.Helper()
+	files := map[string]string{
+		"manifest.json":  `[{"Config":"config.json","RepoTags":["app:dev"],"Layers":["abc/layer.tar"]}]`,
+		"config.json":    `{"architecture":"amd64","os":"linux"}`,
+		"abc/layer.tar":  "uncompressed-layer",
+		"abc/VERSION":    "1.0",
+		"repositories":   `{}`,
+		"abc/json":       `{}`,
+		"another/random": "x",
+	}
+	for k, v := range extra {
+		files[k] = v
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		_ = tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		_, _ = tw.Write([]byte(content))
+	}
+	_ = tw.Close()
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("failed to write docker archive: %v", err)
+	}
+}
+
+func TestImageTags(t *testing.T) {
+	t.Parallel()
+
+	all := []string{"latest", "major", "minor"}
+	tests := []struct {
+		version   string
+		aliases   []string
+		want      []string
+		wantError bool
+	}{
+		{version: "1.2.3", want: []string{"1.2.3"}},
+		{version: "1.2.3", aliases: all, want: []string{"1.2.3", "1.2", "1", "latest"}},
+		{version: "v2.0.0", aliases: []string{"major"}, want: []string{"2.0.0", "2"}},
+		{version: "1.3.0-rc.1", aliases: all, want: []string{"1.3.0-rc.1"}},
+		{version: "1.2.3+build.7", aliases: all, want: []string{"1.2.3-build.7", "1.2", "1", "latest"}},
+		{version: "", wantError: true},
+		{version: "latest", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version+strings.Join(tt.aliases, ","), func(t *testing.T) {
+			got, err := imageTags(tt.version, tt.aliases)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestContainerRegistryURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  *Config
+		want string
+	}{
+		{name: "defaults to gitlab.com registry", cfg: &Config{}, want: "https://registry.gitlab.com"},
+		{name: "derived from self-hosted base URL", cfg: &Config{BaseURL: "https://gitlab.example.com:8443/"}, want: "https://registry.gitlab.example.com"},
+		{name: "explicit host", cfg: &Config{ContainerRegistry: "registry.example.com:5050"}, want: "https://registry.example.com:5050"},
+		{name: "explicit URL", cfg: &Config{ContainerRegistry: "http://127.0.0.1:5000/"}, want: "http://127.0.0.1:5000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := containerRegistryURL(tt.cfg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if u.String() != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, u.String())
+			}
+		})
+	}
+}
+
+func TestParseAuthChallenge(t *testing.T) {
+	t.Parallel()
+
+	scheme, params := parseAuthChallenge(`Bearer realm="https://gitlab.com/jwt/auth",service="container_registry",scope="repository:a/b:pull"`)
+	if scheme != "bearer" {
+		t.Errorf("expected bearer scheme, got %q", scheme)
+	}
+	if params["realm"] != "https://gitlab.com/jwt/auth" || params["service"] != "container_registry" {
+		t.Errorf("unexpected params %v", params)
+	}
+
+	scheme, _ = parseAuthChallenge(`Basic realm="Registry"`)
+	if scheme != "basic" {
+		t.Errorf("expected basic scheme, got %q", scheme)
+	}
+}
+
+func TestPublishContainerImageOCILayout(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+
+	tmpDir := t.TempDir()
+	chdirForTest(t, tmpDir)
+	manifestDesc := writeOCILayout(t, filepath.Join(tmpDir, "image"))
+
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"tag_name": "v1.2.3"}, {"tag_name": "v1.2.2"}]`))
+	})
+	reg := newFakeRegistry(t)
+	cfg := &Config{Token: "glpat-test", ContainerRegistry: reg.server.URL, ImageTags: []string{"minor", "major", "latest"}}
+	p := &GitLabPlugin{}
+
+	published, err := p.uploadReleaseAsset(context.Background(), newTestClient(t, server.URL), cfg, "Group/Project", plugin.ReleaseContext{Version: "1.2.3", TagName: "v1.2.3"}, Asset{Path: "image", Registry: "container", Image: "api"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if len(reg.scopes) != 1 || reg.scopes[0] != "repository:group/project/api:pull,push" {
+		t.Errorf("unexpected token scopes %v", reg.scopes)
+	}
+	if len(reg.blobs) != 2 {
+		t.Errorf("expected config and layer blobs, got %d", len(reg.blobs))
+	}
+	if reg.uploads != 2 {
+		t.Errorf("expected existing blobs to be skipped after the first tag, got %d uploads", reg.uploads)
+	}
+	for _, tag := range []string{"1.2.3", "1.2", "1", "latest"} {
+		key := "group/project/api:" + tag
+		if _, ok := reg.manifests[key]; !ok {
+			t.Errorf("expected manifest for %s", key)
+		}
+		if reg.types[key] != mediaTypeOCIManifest {
+			t.Errorf("expected OCI manifest content type, got %q", reg.types[key])
+		}
+	}
+
+	host := strings.TrimPrefix(reg.server.URL, "http://")
+	if published.link == nil || published.link.LinkType != "image" {
+		t.Fatalf("expected image link, got %+v", published.link)
+	}
+	if published.link.URL != fmt.Sprintf("http://%s/group/project/api:1.2.3", host) {
+		t.Errorf("unexpected link URL %q", published.link.URL)
+	}
+	if published.artifacts[0].Checksum != manifestDesc.Digest {
+		t.Errorf("expected manifest digest %s, got %s", manifestDesc.Digest, published.artifacts[0].Checksum)
+	}
+}
+
+func TestPublishContainerImageDockerArchive(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+
+	tmpDir := t.TempDir()
+	chdirForTest(t, tmpDir)
+	writeDockerArchive(t, filepath.Join(tmpDir, "image.tar"), nil)
+
+	reg := newFakeRegistry(t)
+	cfg := &Config{Token: "glpat-test", ContainerRegistry: reg.server.URL}
+	p := &GitLabPlugin{}
+
+	_, err := p.uploadReleaseAsset(context.Background(), nil, cfg, "group/project", plugin.ReleaseContext{Version: "2.0.0-rc.1", TagName: "v2.0.0-rc.1"}, Asset{Path: "image.tar", Registry: "container"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if len(reg.manifests) != 1 {
+		t.Fatalf("expected only the exact tag for a prerelease, got %v", reg.manifests)
+	}
+	raw, ok := reg.manifests["group/project:2.0.0-rc.1"]
+	if !ok {
+		t.Fatalf("expected manifest for 2.0.0-rc.1")
+	}
+
+	var manifest ociManifestDocument
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		t.Fatalf("invalid manifest: %v", err)
+	}
+	if manifest.Config == nil || manifest.Config.MediaType != mediaTypeOCIConfig {
+		t.Errorf("unexpected config descriptor %+v", manifest.Config)
+	}
+	if len(manifest.Layers) != 1 || manifest.Layers[0].MediaType != mediaTypeOCILayer {
+		t.Errorf("expected one uncompressed layer, got %+v", manifest.Layers)
+	}
+	if string(reg.blobs[manifest.Layers[0].Digest]) != "uncompressed-layer" {
+		t.Error("expected layer blob to be uploaded")
+	}
+}
+
+func TestPublishContainerImageBackport(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+
+	tmpDir := t.TempDir()
+	chdirForTest(t, tmpDir)
+	writeDockerArchive(t, filepath.Join(tmpDir, "image.tar"), nil)
+
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"tag_name": "v2.0.0"}, {"tag_name": "v1.4.2"}]`))
+	})
+	reg := newFakeRegistry(t)
+	cfg := &Config{Token: "glpat-test", ContainerRegistry: reg.server.URL, ImageTags: []string{"minor", "major", "latest"}}
+	p := &GitLabPlugin{}
+
+	_, err := p.uploadReleaseAsset(context.Background(), newTestClient(t, server.URL), cfg, "group/project", plugin.ReleaseContext{Version: "1.4.3", TagName: "v1.4.3"}, Asset{Path: "image.tar", Registry: "container"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	if _, ok := reg.manifests["group/project:1.4.3"]; !ok || len(reg.manifests) != 1 {
+		t.Errorf("expected only the exact tag for a backport, got %v", reg.manifests)
+	}
+}
+
+func TestPublishContainerImageErrors(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+
+	tmpDir := t.TempDir()
+	chdirForTest(t, tmpDir)
+	writeDockerArchive(t, filepath.Join(tmpDir, "traversal.tar"), map[string]string{"../escape": "x"})
+	_ = os.MkdirAll("notlayout", 0755)
+
+	reg := newFakeRegistry(t)
+	p := &GitLabPlugin{}
+	releaseCtx := plugin.ReleaseContext{Version: "1.0.0", TagName: "v1.0.0"}
+
+	tests := []struct {
+		name       string
+		cfg        *Config
+		asset      Asset
+		releaseCtx plugin.ReleaseContext
+		wantError  string
+	}{
+		{
+			name:       "tarball with path traversal",
+			cfg:        &Config{Token: "glpat-test", ContainerRegistry: reg.server.URL},
+			asset:      Asset{Path: "traversal.tar", Registry: "container"},
+			releaseCtx: releaseCtx,
+			wantError:  "path traversal",
+		},
+		{
+			name:       "directory without oci-layout",
+			cfg:        &Config{Token: "glpat-test", ContainerRegistry: reg.server.URL},
+			asset:      Asset{Path: "notlayout", Registry: "container"},
+			releaseCtx: releaseCtx,
+			wantError:  "not an OCI image layout",
+		},
+		{
+			name:       "invalid token",
+			cfg:        &Config{Token: "wrong", ContainerRegistry: reg.server.URL},
+			asset:      Asset{Path: "image.tar", Registry: "container"},
+			releaseCtx: releaseCtx,
+			wantError:  "registry token",
+		},
+		{
+			name:       "non-semver version",
+			cfg:        &Config{Token: "glpat-test", ContainerRegistry: reg.server.URL},
+			asset:      Asset{Path: "image.tar", Registry: "container"},
+			releaseCtx: plugin.ReleaseContext{Version: "nightly"},
+			wantError:  "semantic version",
+		},
+	}
+
+	writeDockerArchive(t, filepath.Join(tmpDir, "image.tar"), nil)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := p.uploadReleaseAsset(context.Background(), nil, tt.cfg, "group/project", tt.releaseCtx, tt.asset)
+			if err == nil || !contains(err.Error(), tt.wantError) {
+				t.Errorf("expected error containing %q, got %v", tt.wantError, err)
+			}
+		})
+	}
+}
+
+func TestContainerRepositoryNumericProjectID(t *testing.T) {
+	t.Parallel()
+
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v4/projects/42" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id": 42, "path_with_namespace": "Acme/Widgets"}`))
+			return
+		}
+		http.NotFound(w, r)
+	})
+
+	p := &GitLabPlugin{}
+	repo, err := p.containerRepository(context.Background(), newTestClient(t, server.URL), "42", "/cli/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo != "acme/widgets/cli" {
+		t.Errorf("expected 'acme/widgets/cli', got %q", repo)
+	}
+}
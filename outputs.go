@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// releaseLink is a link of a release as GitLab returns it, reported in
+// Outputs and the release report.
+type releaseLink struct {
+	ID             int64  `json:"id"`
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	DirectAssetURL string `json:"direct_asset_url,omitempty"`
+	LinkType       string `json:"link_type"`
+}
+
+// releaseMilestone is a milestone associated with a release.
+type releaseMilestone struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// releaseWebURL returns the web URL of a release. GitLab reports it as the
+// release's self link; older instances do not, so it is then derived from the
+// project's web URL.
+func releaseWebURL(ctx context.Context, client *gitlab.Client, projectID, tagName string, release *gitlab.Release) string {
+	if release.Links.Self != "" {
+		return release.Links.Self
+	}
+	return projectWebURL(ctx, client, projectID) + "/-/releases/" + url.PathEscape(tagName)
+}
+
+// projectWebURL returns the web URL of a project. If GitLab cannot be asked,
+// it is built from the project path, which only works for path project IDs.
+func projectWebURL(ctx context.Context, client *gitlab.Client, projectID string) string {
+	project, _, err := client.Projects.GetProject(projectID, nil, gitlab.WithContext(ctx))
+	if err == nil && project.WebURL != "" {
+		return strings.TrimSuffix(project.WebURL, "/")
+	}
+
+	segments := strings.Split(projectID, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return webBaseURL(client) + "/" + strings.Join(segments, "/")
+}
+
+// listReleaseLinks returns the links of a release.
+func listReleaseLinks(ctx context.Context, client *gitlab.Client, projectID, tagName string) ([]releaseLink, error) {
+	links, _, err := client.ReleaseLinks.ListReleaseLinks(projectID, tagName, &gitlab.ListReleaseLinksOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 100},
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list release links: %w", err)
+	}
+
+	result := make([]releaseLink, 0, len(links))
+	for _, link := range links {
+		result = append(result, releaseLink{
+			ID:             link.ID,
+			Name:           link.Name,
+			URL:            link.URL,
+			DirectAssetURL: link.DirectAssetURL,
+			LinkType:       string(link.LinkType),
+		})
+	}
+	return result, nil
+}
+
+// findGenericPackage returns the generic package a release's files were
+// uploaded to, or nil if GitLab does not list it.
+func findGenericPackage(ctx context.Context, client *gitlab.Client, projectID, packageName, tagName string) (*gitlab.Package, error) {
+	version := genericPackageVersion(tagName)
+	packages, _, err := client.Packages.ListProjectPackages(projectID, &gitlab.ListProjectPackagesOptions{
+		PackageType:    gitlab.Ptr("generic"),
+		PackageName:    gitlab.Ptr(packageName),
+		PackageVersion: gitlab.Ptr(version),
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up generic package %s: %w", packageName, err)
+	}
+	// The name filter matches by prefix, so check for the exact package
+	for _, pkg := range packages {
+		if pkg.Name == packageName && pkg.Version == version {
+			return pkg, nil
+		}
+	}
+	return nil, nil
+}
+
+// releaseOutputs describes a created release for plugins that run after this
+// one, so that they do not need to reconstruct GitLab URLs. Problems looking
+// up optional details are returned as warnings.
+func releaseOutputs(ctx context.Context, client *gitlab.Client, projectID, packageName string, release *gitlab.Release, links []releaseLink, artifacts []plugin.Artifact) (map[string]any, []string) {
+	outputs := map[string]any{
+		"release_api_url": fmt.Sprintf("%sprojects/%s/releases/%s", client.BaseURL().String(), gitlab.PathEscape(projectID), gitlab.PathEscape(release.TagName)),
+		"commit_sha":      release.Commit.ID,
+		"asset_links":     links,
+	}
+	if release.CreatedAt != nil {
+		outputs["created_at"] = release.CreatedAt.UTC().Format(time.RFC3339)
+	}
+	if release.ReleasedAt != nil {
+		outputs["released_at"] = release.ReleasedAt.UTC().Format(time.RFC3339)
+	}
+
+	// GitLab lists evidences oldest first
+	for _, evidence := range release.Evidences {
+		if evidence != nil && evidence.SHA != "" {
+			outputs["evidence_sha"] = evidence.SHA
+		}
+	}
+
+	milestones := []releaseMilestone{}
+	for _, milestone := range release.Milestones {
+		if milestone != nil {
+			milestones = append(milestones, releaseMilestone{Title: milestone.Title, URL: milestone.WebURL})
+		}
+	}
+	outputs["milestones"] = milestones
+
+	var warnings []string
+	for _, artifact := range artifacts {
+		if artifact.Type != "generic_package" {
+			continue
+		}
+		pkg, err := findGenericPackage(ctx, client, projectID, packageName, release.TagName)
+		if err != nil {
+			warnings = append(warnings, err.Error())
+		} else if pkg != nil {
+			outputs["package_id"] = pkg.ID
+			if pkg.Links != nil && pkg.Links.WebPath != "" {
+				outputs["package_web_url"] = webBaseURL(client) + pkg.Links.WebPath
+			}
+		}
+		break
+	}
+	return outputs, warnings
+}
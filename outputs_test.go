@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestReleaseWebURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		projectID string
+		tagName   string
+		self      string
+		webURL    string
+		want      string
+	}{
+		{
+			name:      "self link",
+			projectID: "42",
+			tagName:   "v1.0.0",
+			self:      "https://gitlab.example.com/acme/app/-/releases/v1.0.0",
+			want:      "https://gitlab.example.com/acme/app/-/releases/v1.0.0",
+		},
+		{
+			name:      "numeric project ID and path-scoped tag",
+			projectID: "42",
+			tagName:   "sdk/v1.3.0",
+			webURL:    "https://gitlab.example.com/acme/app",
+			want:      "https://gitlab.example.com/acme/app/-/releases/sdk%2Fv1.3.0",
+		},
+		{
+			name:      "project lookup fails",
+			projectID: "acme/my app",
+			tagName:   "v1.0.0+build",
+			want:      "/acme/my%20app/-/releases/v1.0.0+build",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if tt.webURL == "" || r.URL.Path != "/api/v4/projects/"+tt.projectID {
+					http.NotFound(w, r)
+					return
+				}
+				_, _ = w.Write([]byte(`{"id": 42, "web_url": "` + tt.webURL + `"}`))
+			})
+			client, err := gitlab.NewClient("glpat-test", gitlab.WithBaseURL(server.URL+"/api/v4/"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			release := &gitlab.Release{TagName: tt.tagName}
+			release.Links.Self = tt.self
+			want := tt.want
+			if tt.self == "" && tt.webURL == "" {
+				want = server.URL + want
+			}
+			if got := releaseWebURL(context.Background(), client, tt.projectID, tt.tagName, release); got != want {
+				t.Errorf("releaseWebURL() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestExecuteReleaseOutputs(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "app.tar.gz"), []byte("app"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdirForTest(t, tmpDir)
+
+	tests := []struct {
+		name        string
+		packages    string
+		wantPackage bool
+		wantWarning string
+	}{
+		{
+			name: "package found",
+			packages: `[
+				{"id": 40, "name": "release-assets-extra", "version": "v1.4.0", "_links": {"web_path": "/acme/app/-/packages/40"}},
+				{"id": 41, "name": "release-assets", "version": "v1.4.0", "_links": {"web_path": "/acme/app/-/packages/41"}}
+			]`,
+			wantPackage: true,
+		},
+		{
+			name:        "package lookup fails",
+			wantWarning: "failed to look up generic package release-assets",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPut && contains(r.URL.Path, "/packages/generic/"):
+					w.WriteHeader(http.StatusCreated)
+					_, _ = w.Write([]byte(`{}`))
+				case r.Method == http.MethodGet && contains(r.URL.Path, "/packages"):
+					if tt.packages == "" {
+						w.WriteHeader(http.StatusForbidden)
+						_, _ = w.Write([]byte(`{"message": "403 Forbidden"}`))
+						return
+					}
+					if r.URL.Query().Get("package_type") != "generic" || r.URL.Query().Get("package_version") != "v1.4.0" {
+						t.Errorf("unexpected package query %s", r.URL.RawQuery)
+					}
+					_, _ = w.Write([]byte(tt.packages))
+				case r.Method == http.MethodGet && contains(r.URL.Path, "/assets/links"):
+					_, _ = w.Write([]byte(`[{"id": 7, "name": "Docs", "url": "https://docs.example.com", "direct_asset_url": "https://docs.example.com", "link_type": "runbook"}]`))
+				case r.Method == http.MethodPost && contains(r.URL.Path, "/releases"):
+					w.WriteHeader(http.StatusCreated)
+					_, _ = w.Write([]byte(`{
+						"tag_name": "v1.4.0",
+						"name": "Release 1.4.0",
+						"created_at": "2024-06-01T12:00:00Z",
+						"released_at": "2024-06-01T12:00:00Z",
+						"commit": {"id": "abc123"},
+						"milestones": [{"title": "1.4", "web_url": "https://gitlab.example.com/acme/app/-/milestones/3"}],
+						"evidences": [{"sha": "a1f0"}, {"sha": "e2b3"}]
+					}`))
+				default:
+					http.NotFound(w, r)
+				}
+			})
+
+			p := &GitLabPlugin{}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook: plugin.HookPostPublish,
+				Config: map[string]any{
+					"token":      "glpat-test",
+					"base_url":   server.URL,
+					"project_id": "acme/app",
+					"milestones": []any{"1.4"},
+					"assets":     []any{"app.tar.gz"},
+				},
+				Context: plugin.ReleaseContext{Version: "1.4.0", TagName: "v1.4.0"},
+			})
+			if err != nil {
+				t.Fatalf("Execute returned error: %v", err)
+			}
+			if !resp.Success {
+				t.Fatalf("expected success, got %+v", resp)
+			}
+
+			outputs := resp.Outputs
+			if outputs["release_api_url"] != server.URL+"/api/v4/projects/acme%2Fapp/releases/v1%2E4%2E0" {
+				t.Errorf("unexpected release_api_url %v", outputs["release_api_url"])
+			}
+			if outputs["commit_sha"] != "abc123" || outputs["created_at"] != "2024-06-01T12:00:00Z" || outputs["released_at"] != "2024-06-01T12:00:00Z" {
+				t.Errorf("unexpected release details %v", outputs)
+			}
+			if outputs["evidence_sha"] != "e2b3" {
+				t.Errorf("expected the newest evidence_sha, got %v", outputs["evidence_sha"])
+			}
+
+			links := outputs["asset_links"].([]releaseLink)
+			if len(links) != 1 || links[0].ID != 7 || links[0].DirectAssetURL != "https://docs.example.com" {
+				t.Errorf("unexpected asset_links %+v", links)
+			}
+			milestones := outputs["milestones"].([]releaseMilestone)
+			if len(milestones) != 1 || milestones[0].URL != "https://gitlab.example.com/acme/app/-/milestones/3" {
+				t.Errorf("unexpected milestones %+v", milestones)
+			}
+
+			if tt.wantPackage {
+				if outputs["package_id"] != int64(41) || outputs["package_web_url"] != server.URL+"/acme/app/-/packages/41" {
+					t.Errorf("unexpected package outputs %v %v", outputs["package_id"], outputs["package_web_url"])
+				}
+			} else if _, ok := outputs["package_id"]; ok {
+				t.Errorf("unexpected package_id %v", outputs["package_id"])
+			}
+			if tt.wantWarning != "" && !contains(resp.Message, tt.wantWarning) {
+				t.Errorf("expected warning %q in message %q", tt.wantWarning, resp.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// defaultPipelinePollInterval is how often a running pipeline is checked.
+const defaultPipelinePollInterval = 15 * time.Second
+
+// pendingPipelineStatuses are pipeline states that may still turn green.
+var pendingPipelineStatuses = map[string]bool{
+	"created":              true,
+	"waiting_for_resource": true,
+	"preparing":            true,
+	"pending":              true,
+	"running":              true,
+	"scheduled":            true,
+}
+
+// latestPipeline returns the most recent pipeline for the release commit, or
+// for the tag when no commit SHA is known. When running in CI, the job's own
+// pipeline (CI_PIPELINE_ID) is skipped: it is still running and would never
+// let the check pass.
+func latestPipeline(ctx context.Context, client *gitlab.Client, projectID string, releaseCtx plugin.ReleaseContext) (*gitlab.PipelineInfo, error) {
+	opts := &gitlab.ListProjectPipelinesOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 5},
+		OrderBy:     gitlab.Ptr("id"),
+		Sort:        gitlab.Ptr("desc"),
+	}
+	if releaseCtx.CommitSHA != "" {
+		opts.SHA = gitlab.Ptr(releaseCtx.CommitSHA)
+	} else {
+		opts.Ref = gitlab.Ptr(releaseCtx.TagName)
+	}
+
+	pipelines, _, err := client.Pipelines.ListProjectPipelines(projectID, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	current := envValue(releaseCtx, "CI_PIPELINE_ID")
+	for _, pipeline := range pipelines {
+		if strconv.FormatInt(pipeline.ID, 10) != current {
+			return pipeline, nil
+		}
+	}
+	return nil, nil
+}
+
+// checkPipeline refuses to publish unless the latest pipeline for the release
+// commit succeeded. A pending pipeline is polled until pipeline_timeout.
+func (p *GitLabPlugin) checkPipeline(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext) (*plugin.ExecuteResponse, error) {
+	client, err := p.getClient(cfg)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to create GitLab client: %v", err),
+		}, nil
+	}
+
+	projectID := resolveProjectID(cfg, releaseCtx)
+	if projectID == "" {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   "project_id is required (set in config or provide repository owner/name)",
+		}, nil
+	}
+
+	target := releaseCtx.CommitSHA
+	if target == "" {
+		target = releaseCtx.TagName
+	}
+	if target == "" {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   "pipeline check requires a commit SHA or tag name",
+		}, nil
+	}
+
+	// Durations are checked by Validate; invalid values fall back to defaults
+	timeout, _ := time.ParseDuration(cfg.PipelineTimeout)
+	interval, err := time.ParseDuration(cfg.PipelinePollInterval)
+	if err != nil || interval <= 0 {
+		interval = defaultPipelinePollInterval
+	}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		pipeline, err := latestPipeline(ctx, client, projectID, releaseCtx)
+		if err != nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   fmt.Sprintf("failed to look up pipelines for %s: %v", target, err),
+			}, nil
+		}
+		if pipeline == nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   fmt.Sprintf("no pipeline found for %s; refusing to publish an untested commit", target),
+			}, nil
+		}
+
+		switch {
+		case pipeline.Status == "success":
+			return &plugin.ExecuteResponse{
+				Success: true,
+				Message: fmt.Sprintf("Pipeline %d for %s succeeded", pipeline.ID, target),
+				Outputs: map[string]any{
+					"pipeline_id":  pipeline.ID,
+					"pipeline_url": pipeline.WebURL,
+				},
+			}, nil
+		case !pendingPipelineStatuses[pipeline.Status]:
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   fmt.Sprintf("pipeline %d for %s is %s: %s", pipeline.ID, target, pipeline.Status, pipeline.WebURL),
+			}, nil
+		case !time.Now().Add(interval).Before(deadline):
+			hint := "set pipeline_timeout to wait for it"
+			if timeout > 0 {
+				hint = fmt.Sprintf("gave up after %s", timeout)
+			}
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   fmt.Sprintf("pipeline %d for %s is still %s: %s (%s)", pipeline.ID, target, pipeline.Status, pipeline.WebURL, hint),
+			}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   fmt.Sprintf("stopped waiting for pipeline %d: %v", pipeline.ID, ctx.Err()),
+			}, nil
+		case <-time.After(interval):
+		}
+	}
+}
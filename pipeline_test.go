@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// pipelineServer serves an empty freeze period list and the given pipeline
+// statuses in order, repeating the last one. It records the pipeline queries.
+func pipelineServer(t *testing.T, statuses ...string) (string, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var queries []string
+
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/projects/group/project/freeze_periods":
+			_, _ = w.Write([]byte(`[]`))
+		case "/api/v4/projects/group/project/pipelines":
+			mu.Lock()
+			queries = append(queries, r.URL.RawQuery)
+			i := len(queries) - 1
+			mu.Unlock()
+
+			if len(statuses) == 0 {
+				_, _ = w.Write([]byte(`[]`))
+				return
+			}
+			status := statuses[min(i, len(statuses)-1)]
+			_ = json.NewEncoder(w).Encode([]gitlab.PipelineInfo{
+				{ID: 101, Status: status, WebURL: "https://gitlab.example.com/group/project/-/pipelines/101"},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	return server.URL, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), queries...)
+	}
+}
+
+func TestCheckPipeline(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{TagName: "v1.0.0", CommitSHA: "abc123"}
+
+	tests := []struct {
+		name         string
+		statuses     []string
+		timeout      string
+		wantSuccess  bool
+		wantText     string
+		wantRequests int
+	}{
+		{name: "succeeded", statuses: []string{"success"}, wantSuccess: true, wantText: "Pipeline 101 for abc123 succeeded", wantRequests: 1},
+		{name: "failed", statuses: []string{"failed"}, wantText: "pipeline 101 for abc123 is failed", wantRequests: 1},
+		{name: "running without waiting", statuses: []string{"running"}, wantText: "set pipeline_timeout", wantRequests: 1},
+		{name: "running then succeeded", statuses: []string{"pending", "running", "success"}, timeout: "5s", wantSuccess: true, wantText: "succeeded", wantRequests: 3},
+		{name: "running past timeout", statuses: []string{"running"}, timeout: "30ms", wantText: "gave up after 30ms"},
+		{name: "no pipeline", wantText: "no pipeline found for abc123", wantRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverURL, queries := pipelineServer(t, tt.statuses...)
+			cfg := &Config{
+				Token:                "glpat-test",
+				BaseURL:              serverURL,
+				ProjectID:            "group/project",
+				PipelineTimeout:      tt.timeout,
+				PipelinePollInterval: "10ms",
+			}
+
+			p := &GitLabPlugin{}
+			resp, err := p.checkPipeline(context.Background(), cfg, releaseCtx)
+			if err != nil {
+				t.Fatalf("checkPipeline returned error: %v", err)
+			}
+			if resp.Success != tt.wantSuccess {
+				t.Fatalf("expected Success=%v, got %+v", tt.wantSuccess, resp)
+			}
+			if got := resp.Message + resp.Error; !contains(got, tt.wantText) {
+				t.Errorf("expected %q in %q", tt.wantText, got)
+			}
+			if tt.wantRequests > 0 && len(queries()) != tt.wantRequests {
+				t.Errorf("expected %d pipeline lookups, got %d", tt.wantRequests, len(queries()))
+			}
+			if q := queries()[0]; !contains(q, "sha=abc123") || !contains(q, "order_by=id") {
+				t.Errorf("unexpected pipeline query %q", q)
+			}
+		})
+	}
+}
+
+func TestCheckPipelineUsesTagWithoutCommit(t *testing.T) {
+	serverURL, queries := pipelineServer(t, "success")
+	cfg := &Config{Token: "glpat-test", BaseURL: serverURL, ProjectID: "group/project"}
+
+	p := &GitLabPlugin{}
+	resp, err := p.checkPipeline(context.Background(), cfg, plugin.ReleaseContext{TagName: "v1.0.0"})
+	if err != nil || !resp.Success {
+		t.Fatalf("expected success, got %+v (%v)", resp, err)
+	}
+	if q := queries()[0]; !contains(q, "ref=v1.0.0") || contains(q, "sha=") {
+		t.Errorf("expected tag lookup, got %q", q)
+	}
+}
+
+func TestCheckPipelineSkipsCurrentPipeline(t *testing.T) {
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		// The job's own pipeline is newer than the one that tested the commit
+		_ = json.NewEncoder(w).Encode([]gitlab.PipelineInfo{
+			{ID: 102, Status: "running", WebURL: "https://gitlab.example.com/group/project/-/pipelines/102"},
+			{ID: 101, Status: "success", WebURL: "https://gitlab.example.com/group/project/-/pipelines/101"},
+		})
+	})
+	cfg := &Config{Token: "glpat-test", BaseURL: server.URL, ProjectID: "group/project"}
+
+	tests := []struct {
+		name        string
+		pipelineID  string
+		wantSuccess bool
+		wantText    string
+	}{
+		{name: "inside the release pipeline", pipelineID: "102", wantSuccess: true, wantText: "Pipeline 101 for abc123 succeeded"},
+		{name: "outside CI", wantText: "pipeline 102 for abc123 is still running"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			releaseCtx := plugin.ReleaseContext{TagName: "v1.0.0", CommitSHA: "abc123", Environment: map[string]string{"CI_PIPELINE_ID": tt.pipelineID}}
+
+			p := &GitLabPlugin{}
+			resp, err := p.checkPipeline(context.Background(), cfg, releaseCtx)
+			if err != nil {
+				t.Fatalf("checkPipeline returned error: %v", err)
+			}
+			if resp.Success != tt.wantSuccess {
+				t.Fatalf("expected Success=%v, got %+v", tt.wantSuccess, resp)
+			}
+			if got := resp.Message + resp.Error; !contains(got, tt.wantText) {
+				t.Errorf("expected %q in %q", tt.wantText, got)
+			}
+		})
+	}
+}
+
+func TestPrePublishRequiresPipeline(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{TagName: "v1.0.0", CommitSHA: "abc123"}
+	p := &GitLabPlugin{}
+
+	serverURL, queries := pipelineServer(t, "failed")
+	cfg := &Config{Token: "glpat-test", BaseURL: serverURL, ProjectID: "group/project"}
+
+	// Without require_pipeline no check runs
+	resp, err := p.prePublish(context.Background(), nil, cfg, releaseCtx, time.Now())
+	if err != nil || !resp.Success {
+		t.Fatalf("expected success, got %+v (%v)", resp, err)
+	}
+	if len(queries()) != 0 {
+		t.Errorf("expected no pipeline lookup, got %d", len(queries()))
+	}
+
+	cfg.RequirePipeline = true
+	resp, err = p.prePublish(context.Background(), nil, cfg, releaseCtx, time.Now())
+	if err != nil {
+		t.Fatalf("prePublish returned error: %v", err)
+	}
+	if resp.Success || !contains(resp.Error, "is failed") {
+		t.Errorf("expected pipeline failure, got %+v", resp)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// markPublishedAssets flags the planned generic files that the release's
+// generic package already contains.
+func markPublishedAssets(ctx context.Context, client *gitlab.Client, projectID, packageName, tagName string, assets []plannedAsset) error {
+	pkg, err := findGenericPackage(ctx, client, projectID, packageName, tagName)
+	if err != nil || pkg == nil {
+		return err
+	}
+	files, _, err := client.Packages.ListPackageFiles(projectID, pkg.ID, &gitlab.ListPackageFilesOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 100},
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to list files of generic package %s: %w", packageName, err)
+	}
+
+	published := map[string]bool{}
+	for _, file := range files {
+		published[file.FileName] = true
+	}
+	for i := range assets {
+		if assets[i].FileName != "" && published[assets[i].FileName] {
+			assets[i].Published = true
+		}
+	}
+	return nil
+}
+
+// diffPublishedRelease compares what post_publish would publish with the
+// release already published for the tag, so that reviewers can approve
+// changes to published releases before they are made. Nothing is changed.
+func (p *GitLabPlugin) diffPublishedRelease(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext) (*plugin.ExecuteResponse, error) {
+	client, err := p.getClient(cfg)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to create GitLab client: %v", err),
+		}, nil
+	}
+
+	projectID := resolveProjectID(cfg, releaseCtx)
+	if projectID == "" {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   "project_id is required (set in config or provide repository owner/name)",
+		}, nil
+	}
+
+	opts, err := releaseOptions(cfg, releaseCtx)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	tagName := releaseCtx.TagName
+	existing, err := getExistingRelease(ctx, client, projectID, tagName)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+	exists := existing != nil
+	if !exists {
+		existing = &gitlab.Release{}
+	}
+
+	diff := diffRelease(existing, opts, prunableLinks(client, cfg))
+	assets, warnings := planAssets(cfg)
+	if exists {
+		if err := markPublishedAssets(ctx, client, projectID, genericPackageName(cfg), tagName, assets); err != nil {
+			warnings = append(warnings, err.Error())
+		}
+	}
+	diff.AssetsToUpload = assets
+
+	var message string
+	switch changes := diff.summary(); {
+	case !exists:
+		message = fmt.Sprintf("Release %s does not exist yet and would be created", tagName)
+	case len(changes) == 0:
+		message = fmt.Sprintf("Release %s is up to date", tagName)
+	default:
+		message = fmt.Sprintf("Release %s would change: %s", tagName, strings.Join(changes, ", "))
+	}
+
+	return withWarnings(&plugin.ExecuteResponse{
+		Success: true,
+		Message: message,
+		Outputs: map[string]any{
+			"tag_name":       tagName,
+			"project_id":     projectID,
+			"release_exists": exists,
+			"diff":           diff,
+		},
+	}, warnings), nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestExecuteDiffRelease(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "app.tar.gz"), []byte("app"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdirForTest(t, tmpDir)
+
+	published := `{
+		"tag_name": "v1.4.0",
+		"name": "Release 1.4.0",
+		"description": "Notes",
+		"milestones": [{"title": "1.4"}],
+		"assets": {"links": [
+			{"id": 1, "name": "Docs", "url": "https://docs.example.com", "link_type": "runbook"},
+			{"id": 2, "name": "Old", "url": "https://old.example.com", "link_type": "other"}
+		]}
+	}`
+
+	tests := []struct {
+		name          string
+		release       string
+		config        map[string]any
+		wantExists    bool
+		wantMessage   string
+		wantPublished bool
+	}{
+		{
+			name:        "release does not exist",
+			config:      map[string]any{"assets": []any{"app.tar.gz"}},
+			wantMessage: "Release v1.4.0 does not exist yet and would be created",
+		},
+		{
+			name:    "release is up to date",
+			release: published,
+			config: map[string]any{
+				"milestones": []any{"1.4"},
+				"asset_links": []any{
+					map[string]any{"name": "Docs", "url": "https://docs.example.com", "link_type": "runbook"},
+					map[string]any{"name": "Old", "url": "https://old.example.com"},
+				},
+			},
+			wantExists:  true,
+			wantMessage: "Release v1.4.0 is up to date",
+		},
+		{
+			name:    "release would change",
+			release: published,
+			config: map[string]any{
+				"name":            "Acme {version}",
+				"assets":          []any{"app.tar.gz"},
+				"update_existing": true,
+				"prune_links":     true,
+				"asset_links": []any{
+					map[string]any{"name": "Docs", "url": "https://docs.example.com/v1", "link_type": "runbook"},
+					map[string]any{"name": "Checksums", "url": "https://cdn.example.com/sha256sums"},
+				},
+			},
+			wantExists:    true,
+			wantMessage:   "Release v1.4.0 would change: name, milestones (+0 -1), links (+1 -1 ~1), 1 assets to upload",
+			wantPublished: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					t.Errorf("diff sent %s %s", r.Method, r.URL.Path)
+					http.Error(w, "unexpected", http.StatusMethodNotAllowed)
+					return
+				}
+				switch {
+				case contains(r.URL.Path, "/packages/7/package_files"):
+					_, _ = w.Write([]byte(`[{"id": 70, "file_name": "app.tar.gz"}]`))
+				case contains(r.URL.Path, "/packages"):
+					_, _ = w.Write([]byte(`[{"id": 7, "name": "release-assets", "version": "v1.4.0"}]`))
+				case contains(r.URL.Path, "/releases/"):
+					if tt.release == "" {
+						http.NotFound(w, r)
+						return
+					}
+					_, _ = w.Write([]byte(tt.release))
+				default:
+					http.NotFound(w, r)
+				}
+			})
+
+			config := map[string]any{
+				"token":        "glpat-test",
+				"base_url":     server.URL,
+				"project_id":   "acme/app",
+				"diff_release": true,
+			}
+			for key, value := range tt.config {
+				config[key] = value
+			}
+
+			p := &GitLabPlugin{}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook:    plugin.HookPreApprove,
+				Config:  config,
+				Context: plugin.ReleaseContext{Version: "1.4.0", TagName: "v1.4.0", ReleaseNotes: "Notes"},
+			})
+			if err != nil {
+				t.Fatalf("Execute returned error: %v", err)
+			}
+			if !resp.Success {
+				t.Fatalf("expected success, got %+v", resp)
+			}
+			if resp.Message != tt.wantMessage {
+				t.Errorf("expected message %q, got %q", tt.wantMessage, resp.Message)
+			}
+			if resp.Outputs["release_exists"] != tt.wantExists {
+				t.Errorf("expected release_exists %v, got %v", tt.wantExists, resp.Outputs["release_exists"])
+			}
+
+			diff := resp.Outputs["diff"].(*releaseDiff)
+			assets, _ := tt.config["assets"].([]any)
+			if n := len(assets); len(diff.AssetsToUpload) != n {
+				t.Fatalf("expected %d assets to upload, got %+v", n, diff.AssetsToUpload)
+			}
+			if len(diff.AssetsToUpload) > 0 && diff.AssetsToUpload[0].Published != tt.wantPublished {
+				t.Errorf("expected published %v, got %+v", tt.wantPublished, diff.AssetsToUpload[0])
+			}
+		})
+	}
+}
@@ -3,10 +3,16 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 
@@ -34,10 +40,106 @@ type Config struct {
 	ReleasedAt string `json:"released_at,omitempty"`
 	// Milestones is a list of milestones to associate with the release.
 	Milestones []string `json:"milestones,omitempty"`
-	// Assets is a list of files to upload as release assets.
-	Assets []string `json:"assets,omitempty"`
+	// Assets is a list of files or directories to upload as release assets.
+	Assets []Asset `json:"assets,omitempty"`
 	// AssetLinks is a list of external asset links.
 	AssetLinks []AssetLink `json:"asset_links,omitempty"`
+	// ContainerRegistry is the container registry URL (default: registry.<gitlab host>).
+	ContainerRegistry string `json:"container_registry,omitempty"`
+	// ImageTags are the aliases pushed with stable container images ("minor", "major", "latest").
+	ImageTags []string `json:"image_tags,omitempty"`
+	// Provenance attaches a SLSA provenance document for the release assets.
+	Provenance bool `json:"provenance,omitempty"`
+	// CollectEvidence triggers release evidence collection after publishing.
+	CollectEvidence bool `json:"collect_evidence,omitempty"`
+	// SBOM generates a software bill of materials ("cyclonedx" or "spdx").
+	SBOM string `json:"sbom,omitempty"`
+	// SBOMDir is the directory whose Go module the SBOM describes (default: working directory).
+	SBOMDir string `json:"sbom_dir,omitempty"`
+	// CheckFreezePeriods fails pre_publish inside the projects' deploy freeze periods.
+	CheckFreezePeriods bool `json:"check_freeze_periods,omitempty"`
+	// OverrideFreeze publishes even when a deploy freeze is in effect.
+	OverrideFreeze bool `json:"override_freeze,omitempty"`
+	// RequirePipeline refuses to publish unless the release commit's latest pipeline succeeded.
+	RequirePipeline bool `json:"require_pipeline,omitempty"`
+	// PipelineTimeout is how long to wait for a running pipeline (e.g., "30m"; default: no waiting).
+	PipelineTimeout string `json:"pipeline_timeout,omitempty"`
+	// PipelinePollInterval is how often a running pipeline is checked (default: "15s").
+	PipelinePollInterval string `json:"pipeline_poll_interval,omitempty"`
+	// CheckMergeRequests blocks approval when MRs merged since the previous
+	// version lack required approvals or have unresolved threads.
+	CheckMergeRequests bool `json:"check_merge_requests,omitempty"`
+	// DiffRelease reports on pre_approve how publishing would change the
+	// release already published for the tag.
+	DiffRelease bool `json:"diff_release,omitempty"`
+	// Environment records a deployment to this environment on success and failure.
+	Environment string `json:"environment,omitempty"`
+	// EnvironmentURL is the external URL set on the environment.
+	EnvironmentURL string `json:"environment_url,omitempty"`
+	// TriggerPipelines are downstream pipelines started after a successful release.
+	TriggerPipelines []TriggerPipeline `json:"trigger_pipelines,omitempty"`
+	// FailureIssue opens or comments on a release-failure issue on error.
+	FailureIssue bool `json:"failure_issue,omitempty"`
+	// FailureIssueTitle is the issue title template (default: "Release {version} failed").
+	FailureIssueTitle string `json:"failure_issue_title,omitempty"`
+	// FailureIssueDescription is the issue description template.
+	FailureIssueDescription string `json:"failure_issue_description,omitempty"`
+	// FailureIssueAssignees are usernames assigned to new failure issues.
+	FailureIssueAssignees []string `json:"failure_issue_assignees,omitempty"`
+	// Banner is prepended to the release description.
+	Banner string `json:"banner,omitempty"`
+	// Prerelease is the prerelease profile; it is only set while the profile
+	// applies to the release version.
+	Prerelease map[string]any `json:"prerelease,omitempty"`
+	// CheckLatest verifies the latest release permalink and reports asset permalinks.
+	CheckLatest bool `json:"check_latest,omitempty"`
+	// Backports decides how backports that would become the latest release are handled (default: "warn").
+	Backports string `json:"backports,omitempty"`
+	// ReportFile is where a JSON report of the published release is written.
+	ReportFile string `json:"report_file,omitempty"`
+	// UpdateExisting updates the release for the tag if it already exists
+	// instead of failing.
+	UpdateExisting bool `json:"update_existing,omitempty"`
+	// PruneLinks deletes links of an updated release that are not configured.
+	PruneLinks bool `json:"prune_links,omitempty"`
+	// DryRunChecks makes dry runs look up the project, tag, milestones and an existing release.
+	DryRunChecks bool `json:"dry_run_checks,omitempty"`
+	// MaxAssetSize is the largest generic package file, in bytes, that dry runs accept (default: 5 GiB).
+	MaxAssetSize int64 `json:"max_asset_size,omitempty"`
+	// PackageName is the generic package release files are uploaded to (default: "release-assets").
+	PackageName string `json:"package_name,omitempty"`
+	// Targets publishes the release to these projects instead of a single one.
+	Targets []Target `json:"targets,omitempty"`
+	// Components releases each monorepo component under its own path-scoped tag.
+	Components []Component `json:"components,omitempty"`
+	// PartialFailure decides the outcome when some targets or components fail (default: "fail").
+	PartialFailure string `json:"partial_failure,omitempty"`
+}
+
+// Asset represents a file or directory to upload as a release asset.
+type Asset struct {
+	// Path is the file or directory path, relative to the working directory.
+	Path string `json:"path"`
+	// Archive packs a directory into an archive before upload ("tar.gz" or "zip").
+	Archive string `json:"archive,omitempty"`
+	// Registry is the package registry to publish to ("generic", "maven", "npm", "pypi").
+	Registry string `json:"registry,omitempty"`
+	// Pom is the POM file for Maven packages (default: the asset path with a .pom extension).
+	Pom string `json:"pom,omitempty"`
+	// Image is appended to the project path to name a container image (e.g., "api").
+	Image string `json:"image,omitempty"`
+	// Channel is the Helm channel (default: "beta" for prereleases, otherwise "stable").
+	Channel string `json:"channel,omitempty"`
+	// DistTag is the npm dist-tag (default: "next" for prereleases, otherwise "latest").
+	DistTag string `json:"dist_tag,omitempty"`
+	// ModuleName and ModuleSystem identify a Terraform module
+	// (default: parsed from a terraform-<system>-<name> path).
+	ModuleName   string `json:"module_name,omitempty"`
+	ModuleSystem string `json:"module_system,omitempty"`
+	// Distribution is the Debian codename to publish to (e.g., "bookworm").
+	Distribution string `json:"distribution,omitempty"`
+	// Component is the Debian component (default: "main").
+	Component string `json:"component,omitempty"`
 }
 
 // AssetLink represents an external asset link for the release.
@@ -56,6 +158,8 @@ func (p *GitLabPlugin) GetInfo() plugin.Info {
 		Description: "Create GitLab releases and upload assets",
 		Author:      "Relicta Team",
 		Hooks: []plugin.Hook{
+			plugin.HookPreApprove,
+			plugin.HookPrePublish,
 			plugin.HookPostPublish,
 			plugin.HookOnSuccess,
 			plugin.HookOnError,
@@ -71,21 +175,120 @@ func (p *GitLabPlugin) GetInfo() plugin.Info {
 				"ref": {"type": "string", "description": "Tag ref for the release"},
 				"released_at": {"type": "string", "description": "Release date (ISO 8601)"},
 				"milestones": {"type": "array", "items": {"type": "string"}, "description": "Associated milestones"},
-				"assets": {"type": "array", "items": {"type": "string"}, "description": "Files to upload"},
+				"assets": {
+					"type": "array",
+					"items": {
+						"oneOf": [
+							{"type": "string"},
+							{
+								"type": "object",
+								"properties": {
+									"path": {"type": "string"},
+									"archive": {"type": "string", "enum": ["tar.gz", "zip"]},
+									"registry": {"type": "string", "enum": ["generic", "maven", "npm", "pypi", "container", "helm", "terraform", "debian", "rpm"]},
+									"pom": {"type": "string"},
+									"image": {"type": "string"},
+									"channel": {"type": "string"},
+									"dist_tag": {"type": "string"},
+									"module_name": {"type": "string"},
+									"module_system": {"type": "string"},
+									"distribution": {"type": "string"},
+									"component": {"type": "string"}
+								},
+								"required": ["path"]
+							}
+						]
+					},
+					"description": "Files or directories to upload"
+				},
 				"asset_links": {
 					"type": "array",
 					"items": {
 						"type": "object",
 						"properties": {
 							"name": {"type": "string"},
-							"url": {"type": "string"},
-							"filepath": {"type": "string"},
+							"url": {"type": "string", "format": "uri"},
+							"filepath": {"type": "string", "pattern": "^/(?:[-.\\w]+/?)*[0-9a-zA-Z]+$"},
 							"link_type": {"type": "string", "enum": ["other", "runbook", "image", "package"]}
 						},
 						"required": ["name", "url"]
 					},
 					"description": "External asset links"
-				}
+				},
+				"container_registry": {"type": "string", "description": "Container registry URL (default: registry.<gitlab host>)"},
+				"image_tags": {"type": "array", "items": {"type": "string", "enum": ["minor", "major", "latest"]}, "description": "Aliases pushed with stable container images; skipped when a higher version is released"},
+				"provenance": {"type": "boolean", "description": "Attach a SLSA provenance document for the release assets"},
+				"collect_evidence": {"type": "boolean", "description": "Collect release evidence after publishing"},
+				"sbom": {"type": "string", "enum": ["cyclonedx", "spdx"], "description": "Generate and attach an SBOM in this format"},
+				"sbom_dir": {"type": "string", "description": "Directory whose Go module the SBOM describes (default: working directory, or the component directory)"},
+				"check_freeze_periods": {"type": "boolean", "description": "Fail pre_publish inside the deploy freeze periods of every project the release is published to"},
+				"override_freeze": {"type": "boolean", "description": "Publish even when a deploy freeze is in effect"},
+				"require_pipeline": {"type": "boolean", "description": "Require a successful pipeline for the release commit"},
+				"pipeline_timeout": {"type": "string", "description": "How long to wait for a running pipeline (e.g., '30m')"},
+				"pipeline_poll_interval": {"type": "string", "description": "How often to check a running pipeline (default: '15s')"},
+				"check_merge_requests": {"type": "boolean", "description": "Block approval on unapproved or unresolved merge requests in the release range"},
+				"diff_release": {"type": "boolean", "description": "On pre_approve, report how publishing would change the release already published for the tag"},
+				"environment": {"type": "string", "description": "Environment to record deployments in (e.g., 'production')"},
+				"environment_url": {"type": "string", "description": "External URL of the environment"},
+				"trigger_pipelines": {
+					"type": "array",
+					"items": {
+						"type": "object",
+						"properties": {
+							"project": {"type": "string"},
+							"ref": {"type": "string"},
+							"variables": {"type": "object", "additionalProperties": {"type": "string"}},
+							"token_env": {"type": "string"},
+							"wait": {"type": "boolean"},
+							"timeout": {"type": "string"}
+						},
+						"required": ["ref"]
+					},
+					"description": "Downstream pipelines to start after a successful release"
+				},
+				"failure_issue": {"type": "boolean", "description": "Open or comment on a release-failure issue when the release fails"},
+				"failure_issue_title": {"type": "string", "description": "Failure issue title template (default: 'Release {version} failed')"},
+				"failure_issue_description": {"type": "string", "description": "Failure issue description template"},
+				"failure_issue_assignees": {"type": "array", "items": {"type": "string"}, "description": "Usernames to assign new failure issues to"},
+				"targets": {
+					"type": "array",
+					"items": {
+						"type": "object",
+						"properties": {
+							"name": {"type": "string"},
+							"base_url": {"type": "string"},
+							"project_id": {"type": "string"},
+							"token_env": {"type": "string"},
+							"overrides": {"type": "object"}
+						}
+					},
+					"description": "GitLab projects, possibly on other instances, to publish the release to"
+				},
+				"check_latest": {"type": "boolean", "description": "Verify the latest release permalink and report asset permalinks"},
+				"backports": {"type": "string", "enum": ["warn", "adjust"], "description": "Handling of backports that would become the latest release (default: 'warn')"},
+				"report_file": {"type": "string", "description": "Path of a JSON report of the published release"},
+				"update_existing": {"type": "boolean", "description": "Update the release if it already exists, syncing its links with asset_links"},
+				"prune_links": {"type": "boolean", "description": "Delete links of an updated release that are not in asset_links (requires update_existing)"},
+				"dry_run_checks": {"type": "boolean", "description": "Look up the project, tag, milestones and an existing release during dry runs"},
+				"max_asset_size": {"type": "integer", "minimum": 1, "description": "Largest generic package file in bytes that dry runs accept (default: 5 GiB, GitLab's default limit)"},
+				"banner": {"type": "string", "description": "Text prepended to the release description"},
+				"prerelease": {"type": "object", "description": "Options that replace top-level ones when the version has a prerelease suffix; applied only with 'enabled: true'"},
+				"package_name": {"type": "string", "description": "Generic package for release files (default: 'release-assets')"},
+				"components": {
+					"type": "array",
+					"items": {
+						"type": "object",
+						"properties": {
+							"name": {"type": "string"},
+							"version": {"type": "string"},
+							"tag": {"type": "string"},
+							"overrides": {"type": "object"}
+						},
+						"required": ["name"]
+					},
+					"description": "Monorepo components to release under path-scoped tags such as 'sdk/v1.3.0'"
+				},
+				"partial_failure": {"type": "string", "enum": ["fail", "fail_fast", "allow"], "description": "Outcome when some targets or components fail (default: 'fail')"}
 			}
 		}`,
 	}
@@ -93,31 +296,70 @@ func (p *GitLabPlugin) GetInfo() plugin.Info {
 
 // Execute runs the plugin for a given hook.
 func (p *GitLabPlugin) Execute(ctx context.Context, req plugin.ExecuteRequest) (*plugin.ExecuteResponse, error) {
-	cfg := p.parseConfig(req.Config)
+	raw := req.Config
+	cfg := p.parseConfig(raw)
+	if len(cfg.Components) == 0 {
+		// Components apply the profile for their own versions
+		raw = applyPrereleaseProfile(raw, req.Context.Version)
+		cfg = p.parseConfig(raw)
+	}
 
 	switch req.Hook {
+	case plugin.HookPreApprove:
+		if cfg.CheckMergeRequests || cfg.DiffRelease {
+			return p.preApprove(ctx, cfg, req.Context)
+		}
+	case plugin.HookPrePublish:
+		if cfg.CheckFreezePeriods || cfg.RequirePipeline {
+			return p.prePublish(ctx, raw, cfg, req.Context, time.Now())
+		}
 	case plugin.HookPostPublish:
+		if len(cfg.Components) > 0 {
+			return p.publishComponents(ctx, raw, cfg, req.Context, req.DryRun)
+		}
+		if len(cfg.Targets) > 0 {
+			return p.publishTargets(ctx, raw, cfg, req.Context, req.DryRun)
+		}
 		return p.createRelease(ctx, cfg, req.Context, req.DryRun)
 	case plugin.HookOnSuccess:
-		return &plugin.ExecuteResponse{
-			Success: true,
-			Message: "Release successful",
-		}, nil
+		return p.onSuccess(ctx, cfg, req.Context, req.DryRun)
 	case plugin.HookOnError:
-		return &plugin.ExecuteResponse{
-			Success: true,
-			Message: "Release failed notification acknowledged",
-		}, nil
-	default:
-		return &plugin.ExecuteResponse{
-			Success: true,
-			Message: fmt.Sprintf("Hook %s not handled", req.Hook),
-		}, nil
+		return p.onError(ctx, cfg, req.Context, req.DryRun)
 	}
+
+	return &plugin.ExecuteResponse{
+		Success: true,
+		Message: fmt.Sprintf("Hook %s not handled", req.Hook),
+	}, nil
 }
 
-// createRelease creates a GitLab release.
+// createRelease creates a GitLab release and, when report_file is set,
+// writes a report of the result.
 func (p *GitLabPlugin) createRelease(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool) (*plugin.ExecuteResponse, error) {
+	report := newReleaseReport(cfg, releaseCtx, time.Now())
+	resp, err := p.publishRelease(ctx, cfg, releaseCtx, dryRun, report)
+	if err != nil || dryRun || cfg.ReportFile == "" {
+		return resp, err
+	}
+
+	report.finish(resp, time.Now())
+	path := renderTemplate(cfg.ReportFile, releaseCtx)
+	if err := writeReport(path, report); err != nil {
+		// The release is published either way, so a missing report does not
+		// fail it
+		message := fmt.Sprintf("failed to write report %s: %v", path, err)
+		if !resp.Success {
+			resp.Error = strings.TrimPrefix(resp.Error+"; ", "; ") + message
+			return resp, nil
+		}
+		return withWarnings(resp, []string{message}), nil
+	}
+	return resp, nil
+}
+
+// publishRelease creates the release, publishes its assets and records what
+// was published in report.
+func (p *GitLabPlugin) publishRelease(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool, report *releaseReport) (*plugin.ExecuteResponse, error) {
 	// Get GitLab client
 	client, err := p.getClient(cfg)
 	if err != nil {
@@ -128,13 +370,7 @@ func (p *GitLabPlugin) createRelease(ctx context.Context, cfg *Config, releaseCt
 	}
 
 	// Get project ID
-	projectID := cfg.ProjectID
-	if projectID == "" {
-		// Try to construct from repository info
-		if releaseCtx.RepositoryOwner != "" && releaseCtx.RepositoryName != "" {
-			projectID = fmt.Sprintf("%s/%s", releaseCtx.RepositoryOwner, releaseCtx.RepositoryName)
-		}
-	}
+	projectID := resolveProjectID(cfg, releaseCtx)
 
 	if projectID == "" {
 		return &plugin.ExecuteResponse{
@@ -145,7 +381,199 @@ func (p *GitLabPlugin) createRelease(ctx context.Context, cfg *Config, releaseCt
 
 	// Prepare release
 	tagName := releaseCtx.TagName
-	name := cfg.Name
+	releaseOpts, err := releaseOptions(cfg, releaseCtx)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+	report.Project = projectID
+	report.Name = *releaseOpts.Name
+
+	// Generate the SBOM up front so a missing manifest fails before the
+	// release exists
+	var sbomPath string
+	if cfg.SBOM != "" {
+		path, cleanup, err := generateSBOM(cfg.SBOM, cfg.SBOMDir, releaseCtx)
+		if err != nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   fmt.Sprintf("failed to generate SBOM: %v", err),
+			}, nil
+		}
+		defer cleanup()
+		sbomPath = path
+	}
+
+	if dryRun {
+		return p.planRelease(ctx, client, cfg, projectID, releaseCtx, releaseOpts, sbomPath), nil
+	}
+
+	// Keep prereleases and backports from taking over the latest release
+	// permalink
+	var warnings []string
+	var backport bool
+	if cfg.Prerelease != nil {
+		releaseOpts.ReleasedAt, err = prereleaseReleasedAt(ctx, client, projectID, tagName, releaseOpts.ReleasedAt, time.Now())
+		if err != nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   err.Error(),
+			}, nil
+		}
+	} else if cfg.CheckLatest {
+		var warning string
+		releaseOpts.ReleasedAt, backport, warning, err = protectLatest(ctx, client, cfg, projectID, tagName, releaseOpts.ReleasedAt, time.Now())
+		if err != nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   err.Error(),
+			}, nil
+		}
+		if warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+
+	// Create the release, or update it if it exists and update_existing is set
+	started := time.Now()
+	var existing *gitlab.Release
+	if cfg.UpdateExisting {
+		existing, err = getExistingRelease(ctx, client, projectID, tagName)
+		if err != nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   err.Error(),
+			}, nil
+		}
+	}
+	var release *gitlab.Release
+	var changes *releaseDiff
+	if existing != nil {
+		release, changes, err = updateRelease(ctx, client, projectID, existing, releaseOpts, prunableLinks(client, cfg))
+	} else {
+		release, _, err = client.Releases.CreateRelease(projectID, releaseOpts, gitlab.WithContext(ctx))
+		if err != nil {
+			err = fmt.Errorf("failed to create release: %w", err)
+		}
+	}
+	report.Timings.CreateReleaseMS = time.Since(started).Milliseconds()
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	// Upload file assets; a failed asset does not fail the release but is
+	// reported as a warning
+	started = time.Now()
+	var artifacts []plugin.Artifact
+	for _, asset := range expandAssetGlobs(cfg.Assets) {
+		published, err := p.uploadReleaseAsset(ctx, client, cfg, projectID, releaseCtx, asset)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("asset %s not published: %v", asset.Path, err))
+			continue
+		}
+		artifacts = append(artifacts, published.artifacts...)
+		if published.link != nil {
+			if err := p.createAssetLink(ctx, client, projectID, tagName, *published.link); err != nil {
+				warnings = append(warnings, err.Error())
+			}
+		}
+	}
+	report.Timings.AssetsMS = time.Since(started).Milliseconds()
+
+	if sbomPath != "" {
+		artifact, err := p.attachSBOM(ctx, client, projectID, genericPackageName(cfg), tagName, cfg.SBOM, sbomPath)
+		if err != nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   fmt.Sprintf("release %s created but SBOM failed: %v", tagName, err),
+			}, nil
+		}
+		artifacts = append(artifacts, *artifact)
+	}
+
+	// Attach provenance and collect evidence once all assets are linked,
+	// so that the evidence snapshot includes them
+	if cfg.Provenance {
+		artifact, err := p.attachProvenance(ctx, client, projectID, genericPackageName(cfg), releaseCtx, artifacts)
+		if err != nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   fmt.Sprintf("release %s created but provenance failed: %v", tagName, err),
+			}, nil
+		}
+		artifacts = append(artifacts, *artifact)
+	}
+	if cfg.CollectEvidence {
+		if err := collectReleaseEvidence(ctx, client, projectID, tagName); err != nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   fmt.Sprintf("release %s created but evidence collection failed: %v", tagName, err),
+			}, nil
+		}
+	}
+
+	releaseURL := releaseWebURL(ctx, client, projectID, tagName, release)
+
+	// Evidence collected after creation is only part of the release once
+	// it is fetched again
+	if cfg.CollectEvidence {
+		if updated, _, err := client.Releases.GetRelease(projectID, tagName, gitlab.WithContext(ctx)); err == nil {
+			release = updated
+		} else {
+			warnings = append(warnings, fmt.Sprintf("failed to get release %s: %v", tagName, err))
+		}
+	}
+
+	links, err := listReleaseLinks(ctx, client, projectID, tagName)
+	if err != nil {
+		warnings = append(warnings, err.Error())
+	}
+
+	outputs, outputWarnings := releaseOutputs(ctx, client, projectID, genericPackageName(cfg), release, links, artifacts)
+	outputs["release_url"] = releaseURL
+	outputs["tag_name"] = release.TagName
+	outputs["name"] = release.Name
+	warnings = append(warnings, outputWarnings...)
+	if cfg.CheckLatest {
+		latest, latestWarnings, err := latestOutputs(ctx, client, projectID, release, releaseURL, links, backport || cfg.Prerelease != nil)
+		if err != nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   fmt.Sprintf("release %s created but latest release check failed: %v", tagName, err),
+			}, nil
+		}
+		for key, value := range latest {
+			outputs[key] = value
+		}
+		warnings = append(warnings, latestWarnings...)
+	}
+
+	report.addArtifacts(client, cfg, projectID, artifacts)
+	report.Links = append(report.Links, links...)
+
+	message := fmt.Sprintf("Created GitLab release: %s", releaseURL)
+	if changes != nil {
+		message = fmt.Sprintf("Updated GitLab release: %s (links: +%d ~%d -%d)", releaseURL, len(changes.LinksAdded), len(changes.LinksChanged), len(changes.LinksRemoved))
+	}
+	outputs["updated"] = changes != nil
+
+	return withWarnings(&plugin.ExecuteResponse{
+		Success:   true,
+		Message:   message,
+		Outputs:   outputs,
+		Artifacts: artifacts,
+	}, warnings), nil
+}
+
+// releaseOptions builds the request that creates the release.
+func releaseOptions(cfg *Config, releaseCtx plugin.ReleaseContext) (*gitlab.CreateReleaseOptions, error) {
+	tagName := releaseCtx.TagName
+	name := renderTemplate(cfg.Name, releaseCtx)
 	if name == "" {
 		name = fmt.Sprintf("Release %s", releaseCtx.Version)
 	}
@@ -157,30 +585,26 @@ func (p *GitLabPlugin) createRelease(ctx context.Context, cfg *Config, releaseCt
 			description = releaseCtx.Changelog
 		}
 	}
+	if cfg.Banner != "" {
+		description = renderTemplate(cfg.Banner, releaseCtx) + "\n\n" + description
+	}
 
 	ref := cfg.Ref
 	if ref == "" {
 		ref = tagName
 	}
 
-	if dryRun {
-		return &plugin.ExecuteResponse{
-			Success: true,
-			Message: fmt.Sprintf("Would create GitLab release for %s: %s", projectID, tagName),
-			Outputs: map[string]any{
-				"tag_name":   tagName,
-				"project_id": projectID,
-				"name":       name,
-			},
-		}, nil
+	releasedAt, err := parseReleasedAt(cfg.ReleasedAt)
+	if err != nil {
+		return nil, err
 	}
 
-	// Build release options
 	releaseOpts := &gitlab.CreateReleaseOptions{
 		Name:        &name,
 		TagName:     &tagName,
 		Description: &description,
 		Ref:         &ref,
+		ReleasedAt:  releasedAt,
 	}
 
 	// Add milestones if specified
@@ -216,44 +640,140 @@ func (p *GitLabPlugin) createRelease(ctx context.Context, cfg *Config, releaseCt
 			Links: links,
 		}
 	}
+	return releaseOpts, nil
+}
 
-	// Create release
-	release, _, err := client.Releases.CreateRelease(projectID, releaseOpts, gitlab.WithContext(ctx))
-	if err != nil {
+// hookStep is a single action performed for a hook.
+type hookStep func() (*plugin.ExecuteResponse, error)
+
+// runSteps runs every step and combines the responses. The combined response
+// fails if any step failed; later steps still run so that one failing action
+// does not suppress the others.
+func runSteps(steps []hookStep) (*plugin.ExecuteResponse, error) {
+	combined := &plugin.ExecuteResponse{Success: true, Outputs: map[string]any{}}
+	var messages, errs []string
+	for _, step := range steps {
+		resp, err := step()
+		if err != nil {
+			return nil, err
+		}
+		if resp.Success {
+			messages = append(messages, resp.Message)
+		} else {
+			combined.Success = false
+			errs = append(errs, resp.Error)
+		}
+		for key, value := range resp.Outputs {
+			combined.Outputs[key] = value
+		}
+		combined.Artifacts = append(combined.Artifacts, resp.Artifacts...)
+	}
+	combined.Message = strings.Join(messages, "; ")
+	combined.Error = strings.Join(errs, "; ")
+	return combined, nil
+}
+
+// preApprove checks the merged merge requests and reports the changes to an
+// already published release when configured.
+func (p *GitLabPlugin) preApprove(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext) (*plugin.ExecuteResponse, error) {
+	var steps []hookStep
+	if cfg.CheckMergeRequests {
+		steps = append(steps, func() (*plugin.ExecuteResponse, error) {
+			return p.checkMergeRequests(ctx, cfg, releaseCtx)
+		})
+	}
+	if cfg.DiffRelease {
+		steps = append(steps, func() (*plugin.ExecuteResponse, error) {
+			return p.diffPublishedRelease(ctx, cfg, releaseCtx)
+		})
+	}
+	return runSteps(steps)
+}
+
+// onSuccess records the deployment and starts downstream pipelines when configured.
+func (p *GitLabPlugin) onSuccess(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool) (*plugin.ExecuteResponse, error) {
+	var steps []hookStep
+	if cfg.Environment != "" {
+		steps = append(steps, func() (*plugin.ExecuteResponse, error) {
+			return p.recordDeployment(ctx, cfg, releaseCtx, gitlab.DeploymentStatusSuccess, dryRun)
+		})
+	}
+	if len(cfg.TriggerPipelines) > 0 {
+		steps = append(steps, func() (*plugin.ExecuteResponse, error) {
+			return p.triggerPipelines(ctx, cfg, releaseCtx, dryRun)
+		})
+	}
+	if len(steps) == 0 {
 		return &plugin.ExecuteResponse{
-			Success: false,
-			Error:   fmt.Sprintf("failed to create release: %v", err),
+			Success: true,
+			Message: "Release successful",
 		}, nil
 	}
+	return runSteps(steps)
+}
 
-	// Upload file assets
-	var artifacts []plugin.Artifact
-	for _, assetPath := range cfg.Assets {
-		artifact, err := p.uploadAsset(ctx, client, projectID, tagName, assetPath)
-		if err != nil {
-			// Log but don't fail
-			continue
-		}
-		artifacts = append(artifacts, *artifact)
+// onError records the failed deployment when configured.
+func (p *GitLabPlugin) onError(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool) (*plugin.ExecuteResponse, error) {
+	var steps []hookStep
+	if cfg.Environment != "" {
+		steps = append(steps, func() (*plugin.ExecuteResponse, error) {
+			return p.recordDeployment(ctx, cfg, releaseCtx, gitlab.DeploymentStatusFailed, dryRun)
+		})
+	}
+	if cfg.FailureIssue {
+		steps = append(steps, func() (*plugin.ExecuteResponse, error) {
+			return p.reportFailure(ctx, cfg, releaseCtx, dryRun)
+		})
+	}
+	if len(steps) == 0 {
+		return &plugin.ExecuteResponse{
+			Success: true,
+			Message: "Release failed notification acknowledged",
+		}, nil
 	}
+	return runSteps(steps)
+}
 
-	// Construct release URL
-	baseURL := cfg.BaseURL
-	if baseURL == "" {
-		baseURL = "https://gitlab.com"
+// prePublish runs the checks that must pass before publishing when they are
+// enabled: the deploy freeze check and the pipeline check.
+func (p *GitLabPlugin) prePublish(ctx context.Context, raw map[string]any, cfg *Config, releaseCtx plugin.ReleaseContext, now time.Time) (*plugin.ExecuteResponse, error) {
+	var steps []hookStep
+	if cfg.CheckFreezePeriods {
+		steps = append(steps, func() (*plugin.ExecuteResponse, error) {
+			return p.checkDeployFreezes(ctx, raw, cfg, releaseCtx, now)
+		})
+	}
+	if cfg.RequirePipeline {
+		steps = append(steps, func() (*plugin.ExecuteResponse, error) {
+			return p.checkPipeline(ctx, cfg, releaseCtx)
+		})
 	}
-	releaseURL := fmt.Sprintf("%s/%s/-/releases/%s", strings.TrimSuffix(baseURL, "/"), projectID, tagName)
+	return runSteps(steps)
+}
 
-	return &plugin.ExecuteResponse{
-		Success: true,
-		Message: fmt.Sprintf("Created GitLab release: %s", releaseURL),
-		Outputs: map[string]any{
-			"release_url": releaseURL,
-			"tag_name":    release.TagName,
-			"name":        release.Name,
-		},
-		Artifacts: artifacts,
-	}, nil
+// resolveProjectID returns the configured project, falling back to the
+// repository owner and name from the release context.
+func resolveProjectID(cfg *Config, releaseCtx plugin.ReleaseContext) string {
+	if cfg.ProjectID != "" {
+		return cfg.ProjectID
+	}
+	if releaseCtx.RepositoryOwner != "" && releaseCtx.RepositoryName != "" {
+		return fmt.Sprintf("%s/%s", releaseCtx.RepositoryOwner, releaseCtx.RepositoryName)
+	}
+	return ""
+}
+
+// projectPath returns the project's full path as GitLab reports it, resolving
+// numeric project IDs with an API call.
+func projectPath(ctx context.Context, client *gitlab.Client, projectID string) (string, error) {
+	if !isNumericProjectID(projectID) {
+		return projectID, nil
+	}
+	project, _, err := client.Projects.GetProject(projectID, nil, gitlab.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve project %s: %w", projectID, err)
+	}
+	return project.PathWithNamespace, nil
 }
 
 // validateAssetPath validates and sanitizes an asset path to prevent path traversal.
@@ -308,22 +828,32 @@ func validateAssetPath(assetPath string) (string, error) {
 }
 
 // uploadAsset uploads a release asset to GitLab's generic package registry.
-func (p *GitLabPlugin) uploadAsset(ctx context.Context, client *gitlab.Client, projectID, tagName, assetPath string) (*plugin.Artifact, error) {
+func (p *GitLabPlugin) uploadAsset(ctx context.Context, client *gitlab.Client, projectID, packageName, tagName, assetPath string) (*plugin.Artifact, error) {
+	validatedPath, err := validateAssetFile(assetPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.publishGenericFile(ctx, client, projectID, packageName, tagName, validatedPath)
+}
+
+// validateAssetFile validates an asset path and ensures it refers to a regular file.
+func validateAssetFile(assetPath string) (string, error) {
 	// Validate and sanitize the asset path to prevent path traversal
 	validatedPath, err := validateAssetPath(assetPath)
 	if err != nil {
-		return nil, fmt.Errorf("invalid asset path %s: %w", assetPath, err)
+		return "", fmt.Errorf("invalid asset path %s: %w", assetPath, err)
 	}
 
 	// Verify file exists and is a regular file (not a directory)
 	info, err := os.Lstat(validatedPath)
 	if err != nil {
-		return nil, fmt.Errorf("asset file not accessible %s: %w", assetPath, err)
+		return "", fmt.Errorf("asset file not accessible %s: %w", assetPath, err)
 	}
 
 	// Reject directories
 	if info.IsDir() {
-		return nil, fmt.Errorf("asset path is a directory, not a file: %s", assetPath)
+		return "", fmt.Errorf("asset path is a directory, not a file (set archive to upload directories): %s", assetPath)
 	}
 
 	// Note: We already resolved symlinks in validateAssetPath via EvalSymlinks,
@@ -331,37 +861,130 @@ func (p *GitLabPlugin) uploadAsset(ctx context.Context, client *gitlab.Client, p
 	// the working directory boundary. However, we still reject symlinks as a
 	// defense-in-depth measure to prevent any edge cases.
 	if info.Mode()&os.ModeSymlink != 0 {
-		return nil, fmt.Errorf("symlinks not allowed for asset paths: %s", assetPath)
+		return "", fmt.Errorf("symlinks not allowed for asset paths: %s", assetPath)
 	}
 
+	return validatedPath, nil
+}
+
+// expandAssetGlobs replaces asset entries whose path is a glob pattern
+// (e.g., "dist/*.tgz") with one entry per matching path. Patterns without
+// matches are kept so that the upload reports the missing file.
+func expandAssetGlobs(assets []Asset) []Asset {
+	var expanded []Asset
+	for _, asset := range assets {
+		if !strings.ContainsAny(asset.Path, "*?[") {
+			expanded = append(expanded, asset)
+			continue
+		}
+		matches, err := filepath.Glob(asset.Path)
+		if err != nil || len(matches) == 0 {
+			expanded = append(expanded, asset)
+			continue
+		}
+		for _, match := range matches {
+			entry := asset
+			entry.Path = match
+			expanded = append(expanded, entry)
+		}
+	}
+	return expanded
+}
+
+// publishedAsset is the result of uploading a single configured asset.
+type publishedAsset struct {
+	// artifacts lists the files that were uploaded.
+	artifacts []plugin.Artifact
+	// link is added to the release when set.
+	link *AssetLink
+}
+
+// uploadReleaseAsset uploads a configured asset to the package registry it targets,
+// archiving it first if requested.
+func (p *GitLabPlugin) uploadReleaseAsset(ctx context.Context, client *gitlab.Client, cfg *Config, projectID string, releaseCtx plugin.ReleaseContext, asset Asset) (*publishedAsset, error) {
+	switch asset.Registry {
+	case registryContainer:
+		return p.publishContainerImage(ctx, client, cfg, projectID, releaseCtx, asset)
+	case registryHelm:
+		return p.publishHelmChart(ctx, client, projectID, releaseCtx, asset)
+	case registryTerraform:
+		return p.publishTerraformModule(ctx, client, projectID, releaseCtx, asset)
+	case registryDebian:
+		return p.publishDebianPackage(ctx, client, projectID, asset)
+	case registryRPM:
+		return p.publishRPMPackage(ctx, client, projectID, asset)
+	case registryMaven:
+		return p.publishMavenPackage(ctx, client, projectID, releaseCtx, asset)
+	case registryNPM:
+		return p.publishNPMPackage(ctx, client, projectID, asset)
+	case registryPyPI:
+		return p.publishPyPIPackage(ctx, client, projectID, asset)
+	}
+
+	var artifact *plugin.Artifact
+	var err error
+	if asset.Archive != "" {
+		artifact, err = p.uploadArchiveAsset(ctx, client, projectID, genericPackageName(cfg), releaseCtx.TagName, asset)
+	} else {
+		artifact, err = p.uploadAsset(ctx, client, projectID, genericPackageName(cfg), releaseCtx.TagName, asset.Path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &publishedAsset{artifacts: []plugin.Artifact{*artifact}}, nil
+}
+
+// createAssetLink adds a link to an existing release.
+// A link with the same name, e.g. from publishing to an updated release
+// again, is updated instead.
+func (p *GitLabPlugin) createAssetLink(ctx context.Context, client *gitlab.Client, projectID, tagName string, link AssetLink) error {
+	_, _, err := client.ReleaseLinks.CreateReleaseLink(projectID, tagName, createLinkOptions(link), gitlab.WithContext(ctx))
+	if err == nil {
+		return nil
+	}
+
+	if links, listErr := listReleaseLinks(ctx, client, projectID, tagName); listErr == nil {
+		for _, existing := range links {
+			if existing.Name == link.Name {
+				return updateLink(ctx, client, projectID, tagName, existing.ID, link)
+			}
+		}
+	}
+	return fmt.Errorf("failed to create release link %s: %w", link.Name, err)
+}
+
+// publishGenericFile uploads a local file to GitLab's generic package registry.
+// The caller is responsible for validating filePath.
+func (p *GitLabPlugin) publishGenericFile(ctx context.Context, client *gitlab.Client, projectID, packageName, tagName, filePath string) (*plugin.Artifact, error) {
 	// Open file
-	file, err := os.Open(validatedPath)
+	file, err := os.Open(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open asset %s: %w", assetPath, err)
+		return nil, fmt.Errorf("failed to open asset %s: %w", filePath, err)
 	}
 	defer func() { _ = file.Close() }()
 
 	// Get file info
 	fileInfo, err := file.Stat()
 	if err != nil {
-		return nil, fmt.Errorf("failed to stat asset %s: %w", assetPath, err)
+		return nil, fmt.Errorf("failed to stat asset %s: %w", filePath, err)
 	}
 
 	fileName := fileInfo.Name()
 
-	// Upload to GitLab's generic package registry
-	// Package name: release-assets, version: tag name
-	packageName := "release-assets"
+	// Upload to GitLab's generic package registry, versioned by tag name
+	version := genericPackageVersion(tagName)
 	uploadOpts := &gitlab.PublishPackageFileOptions{
 		Status: gitlab.Ptr(gitlab.PackageDefault),
 	}
 
+	// Hash the content while it is read for the upload
+	hash := sha256.New()
 	_, _, err = client.GenericPackages.PublishPackageFile(
 		projectID,
 		packageName,
-		tagName,
+		version,
 		fileName,
-		file,
+		io.TeeReader(file, hash),
 		uploadOpts,
 		gitlab.WithContext(ctx),
 	)
@@ -370,23 +993,45 @@ func (p *GitLabPlugin) uploadAsset(ctx context.Context, client *gitlab.Client, p
 	}
 
 	return &plugin.Artifact{
-		Name: fileName,
-		Path: fmt.Sprintf("packages/generic/%s/%s/%s", packageName, tagName, fileName),
-		Type: "generic_package",
-		Size: fileInfo.Size(),
+		Name:     fileName,
+		Path:     fmt.Sprintf("packages/generic/%s/%s/%s", packageName, version, fileName),
+		Type:     "generic_package",
+		Size:     fileInfo.Size(),
+		Checksum: "sha256:" + hex.EncodeToString(hash.Sum(nil)),
 	}, nil
 }
 
-// getClient creates a GitLab client.
-func (p *GitLabPlugin) getClient(cfg *Config) (*gitlab.Client, error) {
-	token := cfg.Token
-	if token == "" {
-		token = os.Getenv("GITLAB_TOKEN")
-	}
-	if token == "" {
-		token = os.Getenv("GL_TOKEN")
+// genericPackageURL returns the API download URL of a file in the release's
+// generic package.
+func genericPackageURL(client *gitlab.Client, projectID, packageName, tagName, fileName string) string {
+	return fmt.Sprintf("%sprojects/%s/packages/generic/%s/%s/%s",
+		client.BaseURL().String(), gitlab.PathEscape(projectID), gitlab.PathEscape(packageName), gitlab.PathEscape(genericPackageVersion(tagName)), gitlab.PathEscape(fileName))
+}
+
+// genericPackageVersion returns the generic package version for a tag.
+// Package versions cannot contain slashes, so path-scoped tags such as
+// sdk/v1.3.0 become sdk-v1.3.0.
+func genericPackageVersion(tagName string) string {
+	return strings.ReplaceAll(tagName, "/", "-")
+}
+
+// linkFilepathPattern is the format GitLab accepts for release link filepaths.
+var linkFilepathPattern = regexp.MustCompile(`\A/(?:[\-\.\w]+/?)*[\da-zA-Z]+\z`)
+
+// defaultPackageName is the generic package that release files are uploaded to.
+const defaultPackageName = "release-assets"
+
+// genericPackageName returns the configured generic package name.
+func genericPackageName(cfg *Config) string {
+	if cfg.PackageName != "" {
+		return cfg.PackageName
 	}
+	return defaultPackageName
+}
 
+// getClient creates a GitLab client.
+func (p *GitLabPlugin) getClient(cfg *Config) (*gitlab.Client, error) {
+	token := resolveToken(cfg)
 	if token == "" {
 		return nil, fmt.Errorf("GitLab token is required (set GITLAB_TOKEN or configure token)")
 	}
@@ -407,6 +1052,19 @@ func (p *GitLabPlugin) getClient(cfg *Config) (*gitlab.Client, error) {
 	return gitlab.NewClient(token, gitlab.WithBaseURL(baseURL))
 }
 
+// resolveToken returns the configured token, falling back to the
+// GITLAB_TOKEN and GL_TOKEN environment variables.
+func resolveToken(cfg *Config) string {
+	token := cfg.Token
+	if token == "" {
+		token = os.Getenv("GITLAB_TOKEN")
+	}
+	if token == "" {
+		token = os.Getenv("GL_TOKEN")
+	}
+	return token
+}
+
 // parseConfig parses the plugin configuration.
 func (p *GitLabPlugin) parseConfig(raw map[string]any) *Config {
 	cfg := &Config{}
@@ -432,6 +1090,199 @@ func (p *GitLabPlugin) parseConfig(raw map[string]any) *Config {
 	if v, ok := raw["released_at"].(string); ok {
 		cfg.ReleasedAt = v
 	}
+	if v, ok := raw["check_latest"].(bool); ok {
+		cfg.CheckLatest = v
+	}
+	if v, ok := raw["backports"].(string); ok {
+		cfg.Backports = v
+	}
+	if v, ok := raw["report_file"].(string); ok {
+		cfg.ReportFile = v
+	}
+	if v, ok := raw["update_existing"].(bool); ok {
+		cfg.UpdateExisting = v
+	}
+	if v, ok := raw["prune_links"].(bool); ok {
+		cfg.PruneLinks = v
+	}
+	if v, ok := raw["dry_run_checks"].(bool); ok {
+		cfg.DryRunChecks = v
+	}
+	switch v := raw["max_asset_size"].(type) {
+	case float64:
+		cfg.MaxAssetSize = int64(v)
+	case int:
+		cfg.MaxAssetSize = int64(v)
+	case int64:
+		cfg.MaxAssetSize = v
+	}
+	if v, ok := raw["banner"].(string); ok {
+		cfg.Banner = v
+	}
+	if v, ok := raw["prerelease"].(map[string]any); ok {
+		cfg.Prerelease = v
+	}
+	if v, ok := raw["package_name"].(string); ok {
+		cfg.PackageName = v
+	}
+	if v, ok := raw["container_registry"].(string); ok {
+		cfg.ContainerRegistry = v
+	}
+	if v, ok := raw["image_tags"].([]any); ok {
+		for _, t := range v {
+			if s, ok := t.(string); ok {
+				cfg.ImageTags = append(cfg.ImageTags, s)
+			}
+		}
+	}
+	if v, ok := raw["provenance"].(bool); ok {
+		cfg.Provenance = v
+	}
+	if v, ok := raw["collect_evidence"].(bool); ok {
+		cfg.CollectEvidence = v
+	}
+	if v, ok := raw["sbom"].(string); ok {
+		cfg.SBOM = v
+	}
+	if v, ok := raw["sbom_dir"].(string); ok {
+		cfg.SBOMDir = v
+	}
+	if v, ok := raw["check_freeze_periods"].(bool); ok {
+		cfg.CheckFreezePeriods = v
+	}
+	if v, ok := raw["override_freeze"].(bool); ok {
+		cfg.OverrideFreeze = v
+	}
+	if v, ok := raw["require_pipeline"].(bool); ok {
+		cfg.RequirePipeline = v
+	}
+	if v, ok := raw["pipeline_timeout"].(string); ok {
+		cfg.PipelineTimeout = v
+	}
+	if v, ok := raw["pipeline_poll_interval"].(string); ok {
+		cfg.PipelinePollInterval = v
+	}
+	if v, ok := raw["check_merge_requests"].(bool); ok {
+		cfg.CheckMergeRequests = v
+	}
+	if v, ok := raw["diff_release"].(bool); ok {
+		cfg.DiffRelease = v
+	}
+	if v, ok := raw["environment"].(string); ok {
+		cfg.Environment = v
+	}
+	if v, ok := raw["environment_url"].(string); ok {
+		cfg.EnvironmentURL = v
+	}
+
+	// Parse downstream pipeline triggers
+	if v, ok := raw["trigger_pipelines"].([]any); ok {
+		for _, t := range v {
+			entry, ok := t.(map[string]any)
+			if !ok {
+				continue
+			}
+			trigger := TriggerPipeline{}
+			if project, ok := entry["project"].(string); ok {
+				trigger.Project = project
+			}
+			if ref, ok := entry["ref"].(string); ok {
+				trigger.Ref = ref
+			}
+			if vars, ok := entry["variables"].(map[string]any); ok {
+				trigger.Variables = make(map[string]string, len(vars))
+				for key, value := range vars {
+					trigger.Variables[key] = fmt.Sprint(value)
+				}
+			}
+			if tokenEnv, ok := entry["token_env"].(string); ok {
+				trigger.TokenEnv = tokenEnv
+			}
+			if wait, ok := entry["wait"].(bool); ok {
+				trigger.Wait = wait
+			}
+			if timeout, ok := entry["timeout"].(string); ok {
+				trigger.Timeout = timeout
+			}
+			if trigger.Ref != "" {
+				cfg.TriggerPipelines = append(cfg.TriggerPipelines, trigger)
+			}
+		}
+	}
+
+	// Parse failure issue settings
+	if v, ok := raw["failure_issue"].(bool); ok {
+		cfg.FailureIssue = v
+	}
+	if v, ok := raw["failure_issue_title"].(string); ok {
+		cfg.FailureIssueTitle = v
+	}
+	if v, ok := raw["failure_issue_description"].(string); ok {
+		cfg.FailureIssueDescription = v
+	}
+	if v, ok := raw["failure_issue_assignees"].([]any); ok {
+		for _, a := range v {
+			if s, ok := a.(string); ok && s != "" {
+				cfg.FailureIssueAssignees = append(cfg.FailureIssueAssignees, s)
+			}
+		}
+	}
+
+	// Parse publishing targets
+	if v, ok := raw["targets"].([]any); ok {
+		for _, t := range v {
+			entry, ok := t.(map[string]any)
+			if !ok {
+				continue
+			}
+			target := Target{}
+			if name, ok := entry["name"].(string); ok {
+				target.Name = name
+			}
+			if baseURL, ok := entry["base_url"].(string); ok {
+				target.BaseURL = baseURL
+			}
+			if projectID, ok := entry["project_id"].(string); ok {
+				target.ProjectID = projectID
+			}
+			if tokenEnv, ok := entry["token_env"].(string); ok {
+				target.TokenEnv = tokenEnv
+			}
+			if overrides, ok := entry["overrides"].(map[string]any); ok {
+				target.Overrides = overrides
+			}
+			cfg.Targets = append(cfg.Targets, target)
+		}
+	}
+
+	// Parse monorepo components
+	if v, ok := raw["components"].([]any); ok {
+		for _, c := range v {
+			entry, ok := c.(map[string]any)
+			if !ok {
+				continue
+			}
+			component := Component{}
+			if name, ok := entry["name"].(string); ok {
+				component.Name = name
+			}
+			if version, ok := entry["version"].(string); ok {
+				component.Version = version
+			}
+			if tag, ok := entry["tag"].(string); ok {
+				component.Tag = tag
+			}
+			if overrides, ok := entry["overrides"].(map[string]any); ok {
+				component.Overrides = overrides
+			}
+			if component.Name != "" {
+				cfg.Components = append(cfg.Components, component)
+			}
+		}
+	}
+	if v, ok := raw["partial_failure"].(string); ok {
+		cfg.PartialFailure = v
+	}
 
 	// Parse milestones
 	if v, ok := raw["milestones"].([]any); ok {
@@ -442,11 +1293,49 @@ func (p *GitLabPlugin) parseConfig(raw map[string]any) *Config {
 		}
 	}
 
-	// Parse assets
+	// Parse assets (plain paths or objects with options)
 	if v, ok := raw["assets"].([]any); ok {
 		for _, a := range v {
-			if s, ok := a.(string); ok {
-				cfg.Assets = append(cfg.Assets, s)
+			switch asset := a.(type) {
+			case string:
+				cfg.Assets = append(cfg.Assets, Asset{Path: asset})
+			case map[string]any:
+				path, ok := asset["path"].(string)
+				if !ok || path == "" {
+					continue
+				}
+				entry := Asset{Path: path}
+				if archive, ok := asset["archive"].(string); ok {
+					entry.Archive = archive
+				}
+				if registry, ok := asset["registry"].(string); ok {
+					entry.Registry = registry
+				}
+				if pom, ok := asset["pom"].(string); ok {
+					entry.Pom = pom
+				}
+				if image, ok := asset["image"].(string); ok {
+					entry.Image = image
+				}
+				if channel, ok := asset["channel"].(string); ok {
+					entry.Channel = channel
+				}
+				if distTag, ok := asset["dist_tag"].(string); ok {
+					entry.DistTag = distTag
+				}
+				if moduleName, ok := asset["module_name"].(string); ok {
+					entry.ModuleName = moduleName
+				}
+				if moduleSystem, ok := asset["module_system"].(string); ok {
+					entry.ModuleSystem = moduleSystem
+				}
+				if distribution, ok := asset["distribution"].(string); ok {
+					entry.Distribution = distribution
+				}
+				if component, ok := asset["component"].(string); ok {
+					entry.Component = component
+				}
+				cfg.Assets = append(cfg.Assets, entry)
 			}
 		}
 	}
@@ -516,48 +1405,335 @@ func (p *GitLabPlugin) Validate(_ context.Context, config map[string]any) (*plug
 	// Validate assets if provided
 	if assets, ok := config["assets"].([]any); ok {
 		for i, a := range assets {
-			if _, ok := a.(string); !ok {
+			switch asset := a.(type) {
+			case string:
+			case map[string]any:
+				if path, ok := asset["path"].(string); !ok || path == "" {
+					errors = append(errors, plugin.ValidationError{
+						Field:   fmt.Sprintf("assets[%d].path", i),
+						Message: "asset path is required",
+						Code:    "required",
+					})
+				}
+				if archive, ok := asset["archive"]; ok {
+					if s, _ := archive.(string); !validArchiveFormats[s] {
+						errors = append(errors, plugin.ValidationError{
+							Field:   fmt.Sprintf("assets[%d].archive", i),
+							Message: "archive must be one of: tar.gz, zip",
+							Code:    "enum",
+						})
+					}
+				}
+				if archive, _ := asset["archive"].(string); archive != "" {
+					if registry, _ := asset["registry"].(string); registry != "" && registry != registryGeneric {
+						errors = append(errors, plugin.ValidationError{
+							Field:   fmt.Sprintf("assets[%d].archive", i),
+							Message: "archive is only supported for the generic registry",
+							Code:    "conflict",
+						})
+					}
+				}
+				if registry, ok := asset["registry"]; ok {
+					if s, _ := registry.(string); !validRegistries[s] {
+						errors = append(errors, plugin.ValidationError{
+							Field:   fmt.Sprintf("assets[%d].registry", i),
+							Message: "registry must be one of: generic, maven, npm, pypi, container, helm, terraform, debian, rpm",
+							Code:    "enum",
+						})
+					}
+				}
+				if registry, _ := asset["registry"].(string); registry == registryDebian {
+					if distribution, _ := asset["distribution"].(string); distribution == "" {
+						errors = append(errors, plugin.ValidationError{
+							Field:   fmt.Sprintf("assets[%d].distribution", i),
+							Message: "distribution is required for debian packages",
+							Code:    "required",
+						})
+					}
+				}
+			default:
 				errors = append(errors, plugin.ValidationError{
 					Field:   fmt.Sprintf("assets[%d]", i),
-					Message: "asset must be a string",
+					Message: "asset must be a string or an object with a path",
 					Code:    "type",
 				})
 			}
 		}
 	}
 
-	// Validate asset_links if provided
-	if links, ok := config["asset_links"].([]any); ok {
-		for i, linkRaw := range links {
-			if linkMap, ok := linkRaw.(map[string]any); ok {
-				if _, ok := linkMap["name"].(string); !ok {
+	// Validate sbom if provided
+	if sbom, ok := config["sbom"]; ok {
+		if s, _ := sbom.(string); !validSBOMFormats[s] {
+			errors = append(errors, plugin.ValidationError{
+				Field:   "sbom",
+				Message: "sbom must be one of: cyclonedx, spdx",
+				Code:    "enum",
+			})
+		}
+	}
+
+	// Validate pipeline durations if provided
+	for _, field := range []string{"pipeline_timeout", "pipeline_poll_interval"} {
+		if v, ok := config[field].(string); ok && v != "" {
+			if d, err := time.ParseDuration(v); err != nil || d < 0 {
+				errors = append(errors, plugin.ValidationError{
+					Field:   field,
+					Message: fmt.Sprintf("%s must be a duration such as 30s or 10m", field),
+					Code:    "format",
+				})
+			}
+		}
+	}
+
+	// Validate environment_url if provided
+	if envURL, ok := config["environment_url"].(string); ok && envURL != "" {
+		if !strings.HasPrefix(envURL, "https://") && !strings.HasPrefix(envURL, "http://") {
+			errors = append(errors, plugin.ValidationError{
+				Field:   "environment_url",
+				Message: "environment_url must start with http:// or https://",
+				Code:    "format",
+			})
+		}
+	}
+
+	// Validate trigger_pipelines if provided
+	if triggers, ok := config["trigger_pipelines"].([]any); ok {
+		for i, t := range triggers {
+			trigger, ok := t.(map[string]any)
+			if !ok {
+				errors = append(errors, plugin.ValidationError{
+					Field:   fmt.Sprintf("trigger_pipelines[%d]", i),
+					Message: "trigger must be an object with a ref",
+					Code:    "type",
+				})
+				continue
+			}
+			if ref, ok := trigger["ref"].(string); !ok || ref == "" {
+				errors = append(errors, plugin.ValidationError{
+					Field:   fmt.Sprintf("trigger_pipelines[%d].ref", i),
+					Message: "trigger ref is required",
+					Code:    "required",
+				})
+			}
+			if timeout, ok := trigger["timeout"].(string); ok && timeout != "" {
+				if d, err := time.ParseDuration(timeout); err != nil || d <= 0 {
 					errors = append(errors, plugin.ValidationError{
-						Field:   fmt.Sprintf("asset_links[%d].name", i),
-						Message: "asset link name is required",
-						Code:    "required",
+						Field:   fmt.Sprintf("trigger_pipelines[%d].timeout", i),
+						Message: "timeout must be a duration such as 30s or 10m",
+						Code:    "format",
 					})
 				}
-				if _, ok := linkMap["url"].(string); !ok {
+			}
+		}
+	}
+
+	// Validate failure_issue_assignees if provided
+	if assignees, ok := config["failure_issue_assignees"].([]any); ok {
+		for i, a := range assignees {
+			if username, ok := a.(string); !ok || username == "" {
+				errors = append(errors, plugin.ValidationError{
+					Field:   fmt.Sprintf("failure_issue_assignees[%d]", i),
+					Message: "assignee must be a username",
+					Code:    "type",
+				})
+			}
+		}
+	}
+
+	// Validate targets if provided
+	if targets, ok := config["targets"].([]any); ok {
+		baseURL, _ := config["base_url"].(string)
+		for i, t := range targets {
+			target, ok := t.(map[string]any)
+			if !ok {
+				errors = append(errors, plugin.ValidationError{
+					Field:   fmt.Sprintf("targets[%d]", i),
+					Message: "target must be an object",
+					Code:    "type",
+				})
+				continue
+			}
+			if overrides, ok := target["overrides"]; ok {
+				options, ok := overrides.(map[string]any)
+				if !ok {
 					errors = append(errors, plugin.ValidationError{
-						Field:   fmt.Sprintf("asset_links[%d].url", i),
-						Message: "asset link url is required",
-						Code:    "required",
+						Field:   fmt.Sprintf("targets[%d].overrides", i),
+						Message: "overrides must be an object of plugin options",
+						Code:    "type",
 					})
 				}
-				if lt, ok := linkMap["link_type"].(string); ok {
-					validTypes := map[string]bool{"other": true, "runbook": true, "image": true, "package": true}
-					if !validTypes[lt] {
-						errors = append(errors, plugin.ValidationError{
-							Field:   fmt.Sprintf("asset_links[%d].link_type", i),
-							Message: "link_type must be one of: other, runbook, image, package",
-							Code:    "enum",
-						})
-					}
+				if links, ok := options["asset_links"].([]any); ok {
+					errors = append(errors, validateAssetLinks(fmt.Sprintf("targets[%d].overrides.asset_links", i), links)...)
 				}
 			}
+			// target.base_url takes precedence over overrides.base_url
+			overrides, _ := target["overrides"].(map[string]any)
+			targetURL, _ := target["base_url"].(string)
+			urlField := fmt.Sprintf("targets[%d].base_url", i)
+			if targetURL == "" {
+				targetURL, _ = overrides["base_url"].(string)
+				urlField = fmt.Sprintf("targets[%d].overrides.base_url", i)
+			}
+			if targetURL == "" || normalizeBaseURL(targetURL) == normalizeBaseURL(baseURL) {
+				continue
+			}
+			if !strings.HasPrefix(targetURL, "http://") && !strings.HasPrefix(targetURL, "https://") {
+				errors = append(errors, plugin.ValidationError{
+					Field:   urlField,
+					Message: "base_url must start with http:// or https://",
+					Code:    "format",
+				})
+			}
+			if tokenEnv, _ := target["token_env"].(string); tokenEnv == "" && overrides["token"] == nil {
+				errors = append(errors, plugin.ValidationError{
+					Field:   fmt.Sprintf("targets[%d].token_env", i),
+					Message: "token_env is required for targets on another GitLab instance",
+					Code:    "required",
+				})
+			}
+		}
+	}
+
+	// Validate released_at and backports if provided
+	if releasedAt, ok := config["released_at"].(string); ok {
+		if _, err := parseReleasedAt(releasedAt); err != nil {
+			errors = append(errors, plugin.ValidationError{
+				Field:   "released_at",
+				Message: "released_at must be an ISO 8601 timestamp such as 2024-01-02T15:04:05Z",
+				Code:    "format",
+			})
+		}
+	}
+	if tags, ok := config["image_tags"].([]any); ok {
+		for i, t := range tags {
+			if tag, _ := t.(string); !validImageTags[tag] {
+				errors = append(errors, plugin.ValidationError{
+					Field:   fmt.Sprintf("image_tags[%d]", i),
+					Message: "image tag must be one of: minor, major, latest",
+					Code:    "enum",
+				})
+			}
 		}
 	}
 
+	if size, ok := config["max_asset_size"]; ok {
+		valid := false
+		switch v := size.(type) {
+		case float64:
+			valid = v >= 1 && v == float64(int64(v))
+		case int:
+			valid = v >= 1
+		case int64:
+			valid = v >= 1
+		}
+		if !valid {
+			errors = append(errors, plugin.ValidationError{
+				Field:   "max_asset_size",
+				Message: "max_asset_size must be a positive number of bytes",
+				Code:    "type",
+			})
+		}
+	}
+
+	if policy, ok := config["backports"].(string); ok && policy != "" && !validBackportPolicies[policy] {
+		errors = append(errors, plugin.ValidationError{
+			Field:   "backports",
+			Message: "backports must be one of: warn, adjust",
+			Code:    "enum",
+		})
+	}
+
+	// Validate the prerelease profile if provided
+	if profile, ok := config["prerelease"]; ok {
+		options, ok := profile.(map[string]any)
+		if !ok {
+			errors = append(errors, plugin.ValidationError{
+				Field:   "prerelease",
+				Message: "prerelease must be an object of plugin options",
+				Code:    "type",
+			})
+		}
+		if links, ok := options["asset_links"].([]any); ok {
+			errors = append(errors, validateAssetLinks("prerelease.asset_links", links)...)
+		}
+		if enabled, ok := options["enabled"]; ok {
+			if _, ok := enabled.(bool); !ok {
+				errors = append(errors, plugin.ValidationError{
+					Field:   "prerelease.enabled",
+					Message: "enabled must be a boolean",
+					Code:    "type",
+				})
+			}
+		}
+	}
+
+	// Validate components if provided
+	if components, ok := config["components"].([]any); ok {
+		seen := make(map[string]bool, len(components))
+		for i, c := range components {
+			component, ok := c.(map[string]any)
+			if !ok {
+				errors = append(errors, plugin.ValidationError{
+					Field:   fmt.Sprintf("components[%d]", i),
+					Message: "component must be an object with a name",
+					Code:    "type",
+				})
+				continue
+			}
+			name, _ := component["name"].(string)
+			if name == "" {
+				errors = append(errors, plugin.ValidationError{
+					Field:   fmt.Sprintf("components[%d].name", i),
+					Message: "component name is required",
+					Code:    "required",
+				})
+				continue
+			}
+			if seen[name] {
+				errors = append(errors, plugin.ValidationError{
+					Field:   fmt.Sprintf("components[%d].name", i),
+					Message: fmt.Sprintf("component %s is listed more than once", name),
+					Code:    "conflict",
+				})
+			}
+			seen[name] = true
+			if overrides, ok := component["overrides"]; ok {
+				options, ok := overrides.(map[string]any)
+				if !ok {
+					errors = append(errors, plugin.ValidationError{
+						Field:   fmt.Sprintf("components[%d].overrides", i),
+						Message: "overrides must be an object of plugin options",
+						Code:    "type",
+					})
+				}
+				if links, ok := options["asset_links"].([]any); ok {
+					errors = append(errors, validateAssetLinks(fmt.Sprintf("components[%d].overrides.asset_links", i), links)...)
+				}
+			}
+		}
+	}
+	if prune, _ := config["prune_links"].(bool); prune {
+		if update, _ := config["update_existing"].(bool); !update {
+			errors = append(errors, plugin.ValidationError{
+				Field:   "prune_links",
+				Message: "prune_links requires update_existing",
+				Code:    "conflict",
+			})
+		}
+	}
+	if policy, ok := config["partial_failure"].(string); ok && policy != "" && !validPartialFailurePolicies[policy] {
+		errors = append(errors, plugin.ValidationError{
+			Field:   "partial_failure",
+			Message: "partial_failure must be one of: fail, fail_fast, allow",
+			Code:    "enum",
+		})
+	}
+
+	// Validate asset_links if provided
+	if links, ok := config["asset_links"].([]any); ok {
+		errors = append(errors, validateAssetLinks("asset_links", links)...)
+	}
+
 	// Validate milestones if provided
 	if milestones, ok := config["milestones"].([]any); ok {
 		for i, m := range milestones {
@@ -576,3 +1752,77 @@ func (p *GitLabPlugin) Validate(_ context.Context, config map[string]any) (*plug
 		Errors: errors,
 	}, nil
 }
+
+// validateAssetLinks checks the asset links configured under field. GitLab
+// rejects links whose name, URL or filepath is already used by another link
+// of the release.
+func validateAssetLinks(field string, links []any) []plugin.ValidationError {
+	var errors []plugin.ValidationError
+	seen := map[string]map[string]bool{"name": {}, "url": {}, "filepath": {}}
+	unique := func(i int, key, value string) {
+		if seen[key][value] {
+			errors = append(errors, plugin.ValidationError{
+				Field:   fmt.Sprintf("%s[%d].%s", field, i, key),
+				Message: fmt.Sprintf("asset link %s %s is used by another link", key, value),
+				Code:    "conflict",
+			})
+		}
+		seen[key][value] = true
+	}
+	for i, linkRaw := range links {
+		if linkMap, ok := linkRaw.(map[string]any); ok {
+			if name, ok := linkMap["name"].(string); !ok {
+				errors = append(errors, plugin.ValidationError{
+					Field:   fmt.Sprintf("%s[%d].name", field, i),
+					Message: "asset link name is required",
+					Code:    "required",
+				})
+			} else {
+				unique(i, "name", name)
+			}
+			if linkURL, ok := linkMap["url"].(string); !ok {
+				errors = append(errors, plugin.ValidationError{
+					Field:   fmt.Sprintf("%s[%d].url", field, i),
+					Message: "asset link url is required",
+					Code:    "required",
+				})
+			} else if u, err := url.Parse(linkURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				errors = append(errors, plugin.ValidationError{
+					Field:   fmt.Sprintf("%s[%d].url", field, i),
+					Message: "asset link url must be an absolute http:// or https:// URL",
+					Code:    "format",
+				})
+			} else {
+				unique(i, "url", linkURL)
+			}
+			if fp, ok := linkMap["filepath"]; ok {
+				if fp, ok := fp.(string); !ok {
+					errors = append(errors, plugin.ValidationError{
+						Field:   fmt.Sprintf("%s[%d].filepath", field, i),
+						Message: "asset link filepath must be a string",
+						Code:    "type",
+					})
+				} else if !linkFilepathPattern.MatchString(fp) {
+					errors = append(errors, plugin.ValidationError{
+						Field:   fmt.Sprintf("%s[%d].filepath", field, i),
+						Message: "asset link filepath must start with / and contain only letters, digits, '-', '.', '_' and '/', ending in a letter or digit",
+						Code:    "format",
+					})
+				} else {
+					unique(i, "filepath", fp)
+				}
+			}
+			if lt, ok := linkMap["link_type"].(string); ok {
+				validTypes := map[string]bool{"other": true, "runbook": true, "image": true, "package": true}
+				if !validTypes[lt] {
+					errors = append(errors, plugin.ValidationError{
+						Field:   fmt.Sprintf("%s[%d].link_type", field, i),
+						Message: "link_type must be one of: other, runbook, image, package",
+						Code:    "enum",
+					})
+				}
+			}
+		}
+	}
+	return errors
+}
@@ -58,6 +58,8 @@ func TestGetInfo(t *testing.T) {
 
 	t.Run("returns expected hooks", func(t *testing.T) {
 		expectedHooks := []plugin.Hook{
+			plugin.HookPreApprove,
+			plugin.HookPrePublish,
 			plugin.HookPostPublish,
 			plugin.HookOnSuccess,
 			plugin.HookOnError,
@@ -198,6 +200,307 @@ func TestValidate(t *testing.T) {
 			wantValid:  true,
 			wantErrors: 0,
 		},
+		{
+			name: "valid directory asset with archive",
+			config: map[string]any{
+				"token": "glpat-test-token",
+				"assets": []any{
+					"dist/app.zip",
+					map[string]any{"path": "public", "archive": "tar.gz"},
+					map[string]any{"path": "docs", "archive": "zip"},
+				},
+			},
+			wantValid:  true,
+			wantErrors: 0,
+		},
+		{
+			name: "asset object with invalid archive and missing path",
+			config: map[string]any{
+				"token": "glpat-test-token",
+				"assets": []any{
+					map[string]any{"path": "public", "archive": "rar"},
+					map[string]any{"archive": "zip"},
+				},
+			},
+			wantValid:  false,
+			wantErrors: 2,
+			checkErrors: func(t *testing.T, errors []plugin.ValidationError) {
+				if errors[0].Field != "assets[0].archive" || errors[0].Code != "enum" {
+					t.Errorf("expected enum error on 'assets[0].archive', got %q (%s)", errors[0].Field, errors[0].Code)
+				}
+				if errors[1].Field != "assets[1].path" || errors[1].Code != "required" {
+					t.Errorf("expected required error on 'assets[1].path', got %q (%s)", errors[1].Field, errors[1].Code)
+				}
+			},
+		},
+		{
+			name: "debian asset without distribution",
+			config: map[string]any{
+				"token": "glpat-test-token",
+				"assets": []any{
+					map[string]any{"path": "dist/app_1.0.0_amd64.deb", "registry": "debian"},
+					map[string]any{"path": "dist/app_1.0.0_arm64.deb", "registry": "debian", "distribution": "bookworm"},
+				},
+			},
+			wantValid:  false,
+			wantErrors: 1,
+			checkErrors: func(t *testing.T, errors []plugin.ValidationError) {
+				if errors[0].Field != "assets[0].distribution" || errors[0].Code != "required" {
+					t.Errorf("expected required error on 'assets[0].distribution', got %q (%s)", errors[0].Field, errors[0].Code)
+				}
+			},
+		},
+		{
+			name: "invalid sbom format",
+			config: map[string]any{
+				"token": "glpat-test-token",
+				"sbom":  "syft",
+			},
+			wantValid:  false,
+			wantErrors: 1,
+			checkErrors: func(t *testing.T, errors []plugin.ValidationError) {
+				if errors[0].Field != "sbom" || errors[0].Code != "enum" {
+					t.Errorf("expected enum error on 'sbom', got %q (%s)", errors[0].Field, errors[0].Code)
+				}
+			},
+		},
+		{
+			name: "invalid pipeline durations",
+			config: map[string]any{
+				"token":                  "glpat-test-token",
+				"pipeline_timeout":       "ten minutes",
+				"pipeline_poll_interval": "30s",
+			},
+			wantValid:  false,
+			wantErrors: 1,
+			checkErrors: func(t *testing.T, errors []plugin.ValidationError) {
+				if errors[0].Field != "pipeline_timeout" || errors[0].Code != "format" {
+					t.Errorf("expected format error on 'pipeline_timeout', got %q (%s)", errors[0].Field, errors[0].Code)
+				}
+			},
+		},
+		{
+			name: "trigger pipeline missing ref",
+			config: map[string]any{
+				"token": "glpat-test-token",
+				"trigger_pipelines": []any{
+					map[string]any{"project": "docs/site", "ref": "main"},
+					map[string]any{"project": "acme/homebrew-tap", "timeout": "1h"},
+				},
+			},
+			wantValid:  false,
+			wantErrors: 1,
+			checkErrors: func(t *testing.T, errors []plugin.ValidationError) {
+				if errors[0].Field != "trigger_pipelines[1].ref" || errors[0].Code != "required" {
+					t.Errorf("expected required error on 'trigger_pipelines[1].ref', got %q (%s)", errors[0].Field, errors[0].Code)
+				}
+			},
+		},
+		{
+			name: "failure issue assignee not a username",
+			config: map[string]any{
+				"token":                   "glpat-test-token",
+				"failure_issue":           true,
+				"failure_issue_assignees": []any{"alice", 42},
+			},
+			wantValid:  false,
+			wantErrors: 1,
+			checkErrors: func(t *testing.T, errors []plugin.ValidationError) {
+				if errors[0].Field != "failure_issue_assignees[1]" || errors[0].Code != "type" {
+					t.Errorf("expected type error on 'failure_issue_assignees[1]', got %q (%s)", errors[0].Field, errors[0].Code)
+				}
+			},
+		},
+		{
+			name: "target on another instance without token",
+			config: map[string]any{
+				"token":    "glpat-test-token",
+				"base_url": "https://gitlab.internal.example.com",
+				"targets": []any{
+					map[string]any{"project_id": "platform/app-mirror"},
+					map[string]any{"base_url": "https://gitlab.com", "project_id": "acme/app"},
+				},
+				"partial_failure": "fail",
+			},
+			wantValid:  false,
+			wantErrors: 1,
+			checkErrors: func(t *testing.T, errors []plugin.ValidationError) {
+				if errors[0].Field != "targets[1].token_env" || errors[0].Code != "required" {
+					t.Errorf("expected required error on 'targets[1].token_env', got %q (%s)", errors[0].Field, errors[0].Code)
+				}
+			},
+		},
+		{
+			name: "target on another instance through overrides",
+			config: map[string]any{
+				"token":    "glpat-test-token",
+				"base_url": "https://gitlab.internal.example.com",
+				"targets": []any{
+					map[string]any{"project_id": "acme/app", "overrides": map[string]any{"base_url": "gitlab.com"}},
+				},
+			},
+			wantValid:  false,
+			wantErrors: 2,
+			checkErrors: func(t *testing.T, errors []plugin.ValidationError) {
+				if errors[0].Field != "targets[0].overrides.base_url" || errors[0].Code != "format" {
+					t.Errorf("expected format error on 'targets[0].overrides.base_url', got %q (%s)", errors[0].Field, errors[0].Code)
+				}
+				if errors[1].Field != "targets[0].token_env" || errors[1].Code != "required" {
+					t.Errorf("expected required error on 'targets[0].token_env', got %q (%s)", errors[1].Field, errors[1].Code)
+				}
+			},
+		},
+		{
+			name: "invalid partial_failure",
+			config: map[string]any{
+				"token":           "glpat-test-token",
+				"partial_failure": "ignore",
+			},
+			wantValid:  false,
+			wantErrors: 1,
+			checkErrors: func(t *testing.T, errors []plugin.ValidationError) {
+				if errors[0].Field != "partial_failure" || errors[0].Code != "enum" {
+					t.Errorf("expected enum error on 'partial_failure', got %q (%s)", errors[0].Field, errors[0].Code)
+				}
+			},
+		},
+		{
+			name: "duplicate component",
+			config: map[string]any{
+				"token": "glpat-test-token",
+				"components": []any{
+					map[string]any{"name": "sdk"},
+					map[string]any{"name": "cli"},
+					map[string]any{"name": "sdk", "version": "1.3.0"},
+				},
+			},
+			wantValid:  false,
+			wantErrors: 1,
+			checkErrors: func(t *testing.T, errors []plugin.ValidationError) {
+				if errors[0].Field != "components[2].name" || errors[0].Code != "conflict" {
+					t.Errorf("expected conflict error on 'components[2].name', got %q (%s)", errors[0].Field, errors[0].Code)
+				}
+			},
+		},
+		{
+			name: "invalid image_tags",
+			config: map[string]any{
+				"token":      "glpat-test-token",
+				"image_tags": []any{"latest", "edge"},
+			},
+			wantValid:  false,
+			wantErrors: 1,
+			checkErrors: func(t *testing.T, errors []plugin.ValidationError) {
+				if errors[0].Field != "image_tags[1]" || errors[0].Code != "enum" {
+					t.Errorf("expected enum error on 'image_tags[1]', got %q (%s)", errors[0].Field, errors[0].Code)
+				}
+			},
+		},
+		{
+			name: "prune_links without update_existing",
+			config: map[string]any{
+				"token":       "glpat-test-token",
+				"prune_links": true,
+			},
+			wantValid:  false,
+			wantErrors: 1,
+			checkErrors: func(t *testing.T, errors []plugin.ValidationError) {
+				if errors[0].Field != "prune_links" || errors[0].Code != "conflict" {
+					t.Errorf("expected conflict error on 'prune_links', got %q (%s)", errors[0].Field, errors[0].Code)
+				}
+			},
+		},
+		{
+			name: "invalid asset_links in target overrides and prerelease profile",
+			config: map[string]any{
+				"token": "glpat-test-token",
+				"targets": []any{
+					map[string]any{"project_id": "acme/app-mirror", "overrides": map[string]any{
+						"asset_links": []any{
+							map[string]any{"name": "Docs", "url": "https://docs.example.com"},
+							map[string]any{"name": "Docs", "url": "docs.example.com"},
+						},
+					}},
+				},
+				"prerelease": map[string]any{
+					"asset_links": []any{map[string]any{"name": "Binary", "url": "https://cdn.example.com/app", "filepath": "bin/app"}},
+				},
+			},
+			wantValid:  false,
+			wantErrors: 3,
+			checkErrors: func(t *testing.T, errors []plugin.ValidationError) {
+				want := []struct{ field, code string }{
+					{"targets[0].overrides.asset_links[1].name", "conflict"},
+					{"targets[0].overrides.asset_links[1].url", "format"},
+					{"prerelease.asset_links[0].filepath", "format"},
+				}
+				for i, w := range want {
+					if errors[i].Field != w.field || errors[i].Code != w.code {
+						t.Errorf("expected %s error on %q, got %q (%s)", w.code, w.field, errors[i].Field, errors[i].Code)
+					}
+				}
+			},
+		},
+		{
+			name: "invalid max_asset_size",
+			config: map[string]any{
+				"token":          "glpat-test-token",
+				"max_asset_size": float64(-1),
+			},
+			wantValid:  false,
+			wantErrors: 1,
+			checkErrors: func(t *testing.T, errors []plugin.ValidationError) {
+				if errors[0].Field != "max_asset_size" || errors[0].Code != "type" {
+					t.Errorf("expected type error on 'max_asset_size', got %q (%s)", errors[0].Field, errors[0].Code)
+				}
+			},
+		},
+		{
+			name: "prerelease profile not an object",
+			config: map[string]any{
+				"token":      "glpat-test-token",
+				"prerelease": true,
+			},
+			wantValid:  false,
+			wantErrors: 1,
+			checkErrors: func(t *testing.T, errors []plugin.ValidationError) {
+				if errors[0].Field != "prerelease" || errors[0].Code != "type" {
+					t.Errorf("expected type error on 'prerelease', got %q (%s)", errors[0].Field, errors[0].Code)
+				}
+			},
+		},
+		{
+			name: "prerelease enabled not a boolean",
+			config: map[string]any{
+				"token":      "glpat-test-token",
+				"prerelease": map[string]any{"enabled": "yes"},
+			},
+			wantValid:  false,
+			wantErrors: 1,
+			checkErrors: func(t *testing.T, errors []plugin.ValidationError) {
+				if errors[0].Field != "prerelease.enabled" || errors[0].Code != "type" {
+					t.Errorf("expected type error on 'prerelease.enabled', got %q (%s)", errors[0].Field, errors[0].Code)
+				}
+			},
+		},
+		{
+			name: "invalid released_at and backports",
+			config: map[string]any{
+				"token":       "glpat-test-token",
+				"released_at": "June 1st",
+				"backports":   "ignore",
+			},
+			wantValid:  false,
+			wantErrors: 2,
+			checkErrors: func(t *testing.T, errors []plugin.ValidationError) {
+				if errors[0].Field != "released_at" || errors[0].Code != "format" {
+					t.Errorf("expected format error on 'released_at', got %q (%s)", errors[0].Field, errors[0].Code)
+				}
+				if errors[1].Field != "backports" || errors[1].Code != "enum" {
+					t.Errorf("expected enum error on 'backports', got %q (%s)", errors[1].Field, errors[1].Code)
+				}
+			},
+		},
 		{
 			name: "asset_link missing name",
 			config: map[string]any{
@@ -267,6 +570,52 @@ func TestValidate(t *testing.T) {
 			wantValid:  true,
 			wantErrors: 0,
 		},
+		{
+			name: "asset_link invalid url and filepath",
+			config: map[string]any{
+				"token": "glpat-test-token",
+				"asset_links": []any{
+					map[string]any{"name": "Relative", "url": "/downloads/app.zip"},
+					map[string]any{"name": "FTP", "url": "ftp://example.com/app.zip"},
+					map[string]any{"name": "No slash", "url": "https://example.com/1", "filepath": "bin/app"},
+					map[string]any{"name": "Trailing dot", "url": "https://example.com/2", "filepath": "/bin/app."},
+					map[string]any{"name": "Space", "url": "https://example.com/3", "filepath": "/bin/my app"},
+					map[string]any{"name": "Valid", "url": "https://example.com/4", "filepath": "/bin/app-linux_amd64.tar.gz"},
+				},
+			},
+			wantValid:  false,
+			wantErrors: 5,
+			checkErrors: func(t *testing.T, errors []plugin.ValidationError) {
+				want := []string{"asset_links[0].url", "asset_links[1].url", "asset_links[2].filepath", "asset_links[3].filepath", "asset_links[4].filepath"}
+				for i, field := range want {
+					if errors[i].Field != field || errors[i].Code != "format" {
+						t.Errorf("expected format error on %q, got %q (%s)", field, errors[i].Field, errors[i].Code)
+					}
+				}
+			},
+		},
+		{
+			name: "asset_link duplicates",
+			config: map[string]any{
+				"token": "glpat-test-token",
+				"asset_links": []any{
+					map[string]any{"name": "Binary", "url": "https://example.com/app", "filepath": "/bin/app"},
+					map[string]any{"name": "Binary", "url": "https://example.com/app-v2", "filepath": "/bin/app-v2"},
+					map[string]any{"name": "Mirror", "url": "https://example.com/app", "filepath": "/bin/mirror"},
+					map[string]any{"name": "Copy", "url": "https://example.com/copy", "filepath": "/bin/app"},
+				},
+			},
+			wantValid:  false,
+			wantErrors: 3,
+			checkErrors: func(t *testing.T, errors []plugin.ValidationError) {
+				want := []string{"asset_links[1].name", "asset_links[2].url", "asset_links[3].filepath"}
+				for i, field := range want {
+					if errors[i].Field != field || errors[i].Code != "conflict" {
+						t.Errorf("expected conflict error on %q, got %q (%s)", field, errors[i].Field, errors[i].Code)
+					}
+				}
+			},
+		},
 		{
 			name: "invalid milestone type",
 			config: map[string]any{
@@ -427,8 +776,8 @@ func TestParseConfig(t *testing.T) {
 					t.Fatalf("assets: expected %d, got %d", len(expected), len(cfg.Assets))
 				}
 				for i, a := range expected {
-					if cfg.Assets[i] != a {
-						t.Errorf("assets[%d]: expected %q, got %q", i, a, cfg.Assets[i])
+					if cfg.Assets[i].Path != a {
+						t.Errorf("assets[%d]: expected %q, got %q", i, a, cfg.Assets[i].Path)
 					}
 				}
 			},
@@ -496,6 +845,42 @@ func TestParseConfig(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "parses asset objects",
+			raw: map[string]any{
+				"assets": []any{
+					"dist/app.zip",
+					map[string]any{"path": "public", "archive": "zip"},
+					map[string]any{"archive": "tar.gz"}, // Missing path
+				},
+			},
+			validate: func(t *testing.T, cfg *Config) {
+				if len(cfg.Assets) != 2 {
+					t.Fatalf("assets: expected 2 entries, got %d", len(cfg.Assets))
+				}
+				if cfg.Assets[0].Path != "dist/app.zip" || cfg.Assets[0].Archive != "" {
+					t.Errorf("assets[0]: unexpected %+v", cfg.Assets[0])
+				}
+				if cfg.Assets[1].Path != "public" || cfg.Assets[1].Archive != "zip" {
+					t.Errorf("assets[1]: unexpected %+v", cfg.Assets[1])
+				}
+			},
+		},
+		{
+			name: "parses provenance and evidence options",
+			raw: map[string]any{
+				"provenance":       true,
+				"collect_evidence": "yes", // Should be bool
+			},
+			validate: func(t *testing.T, cfg *Config) {
+				if !cfg.Provenance {
+					t.Error("provenance: expected true")
+				}
+				if cfg.CollectEvidence {
+					t.Error("collect_evidence: expected false for non-bool value")
+				}
+			},
+		},
 		{
 			name: "ignores invalid types in arrays",
 			raw: map[string]any{
@@ -1042,7 +1427,7 @@ func TestUploadAssetValidation(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Note: We can't test actual upload without a real GitLab client,
 			// but we can test the validation logic
-			artifact, err := p.uploadAsset(ctx, nil, "group/project", "v1.0.0", tt.assetPath)
+			artifact, err := p.uploadAsset(ctx, nil, "group/project", defaultPackageName, "v1.0.0", tt.assetPath)
 
 			if tt.wantError {
 				if err == nil {
@@ -1459,7 +1844,7 @@ func TestConfigStructFields(t *testing.T) {
 		Ref:         "main",
 		ReleasedAt:  "2024-01-15T10:00:00Z",
 		Milestones:  []string{"v1.0.0", "v1.1.0"},
-		Assets:      []string{"file1.zip", "file2.tar.gz"},
+		Assets:      []Asset{{Path: "file1.zip"}, {Path: "file2.tar.gz"}},
 		AssetLinks: []AssetLink{
 			{Name: "Link1", URL: "https://example.com/1", FilePath: "/path", LinkType: "package"},
 		},
@@ -2069,7 +2454,7 @@ func TestUploadAssetFileOperations(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			artifact, err := p.uploadAsset(ctx, nil, "group/project", "v1.0.0", tt.assetPath)
+			artifact, err := p.uploadAsset(ctx, nil, "group/project", defaultPackageName, "v1.0.0", tt.assetPath)
 
 			if tt.wantError {
 				if err == nil {
@@ -2806,28 +3191,28 @@ func TestCreateReleaseWithAssetsAndMockedAPI(t *testing.T) {
 
 	tests := []struct {
 		name          string
-		assets        []string
+		assets        []Asset
 		serverHandler http.HandlerFunc
 		wantSuccess   bool
 		wantArtifacts int
 	}{
 		{
 			name:          "release with successful asset upload",
-			assets:        []string{"app.zip"},
+			assets:        []Asset{{Path: "app.zip"}},
 			serverHandler: releaseAndPackageHandler,
 			wantSuccess:   true,
 			wantArtifacts: 1,
 		},
 		{
 			name:          "release with failed asset upload continues",
-			assets:        []string{"app.zip"},
+			assets:        []Asset{{Path: "app.zip"}},
 			serverHandler: releaseOnlyHandler,
 			wantSuccess:   true,
 			wantArtifacts: 0, // Asset upload failed but release succeeded
 		},
 		{
 			name:   "release with non-existent asset",
-			assets: []string{"nonexistent.zip"},
+			assets: []Asset{{Path: "nonexistent.zip"}},
 			serverHandler: func(w http.ResponseWriter, r *http.Request) {
 				if contains(r.URL.Path, "/releases") && r.Method == "POST" {
 					w.Header().Set("Content-Type", "application/json")
@@ -2949,7 +3334,7 @@ func TestUploadAssetWithMockedAPI(t *testing.T) {
 				t.Fatalf("failed to create client: %v", err)
 			}
 
-			artifact, err := p.uploadAsset(ctx, client, "group/project", "v1.0.0", tt.assetPath)
+			artifact, err := p.uploadAsset(ctx, client, "group/project", defaultPackageName, "v1.0.0", tt.assetPath)
 
 			if tt.wantError {
 				if err == nil {
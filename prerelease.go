@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// Defaults of the prerelease profile, applied when the profile is enabled,
+// the release version has a prerelease suffix and the profile does not set
+// the option itself.
+const (
+	defaultPrereleaseBanner = "> **Pre-release:** {version} is a pre-release and may be unstable. It is not recommended for production use."
+	// prereleaseNameSuffix marks prereleases in the default release name.
+	prereleaseNameSuffix = " (pre-release)"
+)
+
+// isPrerelease reports whether version is a semantic version with a
+// prerelease suffix such as -rc.1 or -beta.
+func isPrerelease(version string) bool {
+	v, err := parseSemver(version)
+	return err == nil && v.IsPrerelease()
+}
+
+// applyPrereleaseProfile returns the raw configuration to use for version.
+// The profile is opt-in: with "prerelease.enabled" set and a prerelease
+// version, the name is marked and a banner is added, then the options under
+// "prerelease" replace the top-level ones. The profile is only kept in the
+// result when it was applied, so Config.Prerelease tells whether it was.
+func applyPrereleaseProfile(raw map[string]any, version string) map[string]any {
+	profile, hasProfile := raw["prerelease"].(map[string]any)
+	enabled, _ := profile["enabled"].(bool)
+	active := enabled && isPrerelease(version)
+	if !active && !hasProfile {
+		return raw
+	}
+
+	merged := make(map[string]any, len(raw)+len(profile))
+	for key, value := range raw {
+		if key != "prerelease" {
+			merged[key] = value
+		}
+	}
+	if !active {
+		return merged
+	}
+
+	name, _ := raw["name"].(string)
+	if name == "" {
+		name = "Release {version}"
+	}
+	merged["name"] = name + prereleaseNameSuffix
+	merged["banner"] = defaultPrereleaseBanner
+
+	for key, value := range profile {
+		if key != "enabled" {
+			merged[key] = value
+		}
+	}
+	merged["prerelease"] = profile
+	return merged
+}
+
+// prereleaseReleasedAt returns the released_at of a prerelease so that it
+// does not take over the latest release permalink: one second before the
+// current latest release, unless it is already earlier. A project without
+// releases keeps the configured released_at.
+func prereleaseReleasedAt(ctx context.Context, client *gitlab.Client, projectID, tagName string, releasedAt *time.Time, now time.Time) (*time.Time, error) {
+	latest, err := latestRelease(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil || latest.TagName == tagName || latest.ReleasedAt == nil {
+		return releasedAt, nil
+	}
+
+	effective := now
+	if releasedAt != nil {
+		effective = *releasedAt
+	}
+	if effective.Before(*latest.ReleasedAt) {
+		return releasedAt, nil
+	}
+	adjusted := latest.ReleasedAt.Add(-time.Second)
+	return &adjusted, nil
+}
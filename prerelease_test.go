@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestApplyPrereleaseProfile(t *testing.T) {
+	raw := map[string]any{
+		"name":       "Acme {version}",
+		"milestones": []any{"1.0"},
+		"assets":     []any{"dist/*.tar.gz"},
+		"prerelease": map[string]any{"enabled": true},
+	}
+
+	tests := []struct {
+		name        string
+		raw         map[string]any
+		version     string
+		wantName    string
+		wantBanner  string
+		wantPackage string
+		wantProfile bool
+	}{
+		{
+			name:     "GA release is unchanged",
+			raw:      raw,
+			version:  "1.0.0",
+			wantName: "Acme {version}",
+		},
+		{
+			name:        "release candidate gets the defaults",
+			raw:         raw,
+			version:     "1.0.0-rc.1",
+			wantName:    "Acme {version} (pre-release)",
+			wantBanner:  defaultPrereleaseBanner,
+			wantProfile: true,
+		},
+		{
+			name: "profile options win",
+			raw: map[string]any{
+				"package_name": "acme",
+				"milestones":   []any{"1.0"},
+				"prerelease":   map[string]any{"enabled": true, "name": "Acme {version} RC", "banner": "", "package_name": "acme-rc"},
+			},
+			version:     "v1.0.0-beta",
+			wantName:    "Acme {version} RC",
+			wantPackage: "acme-rc",
+			wantProfile: true,
+		},
+		{
+			name: "profile without enabled",
+			raw: map[string]any{
+				"name":       "Acme {version}",
+				"milestones": []any{"1.0"},
+				"prerelease": map[string]any{"name": "unused"},
+			},
+			version:  "1.0.0-rc.1",
+			wantName: "Acme {version}",
+		},
+		{
+			name: "disabled profile",
+			raw: map[string]any{
+				"name":       "Acme {version}",
+				"milestones": []any{"1.0"},
+				"prerelease": map[string]any{"enabled": false, "name": "unused"},
+			},
+			version:  "1.0.0-rc.1",
+			wantName: "Acme {version}",
+		},
+		{
+			name:     "non-semver version",
+			raw:      map[string]any{"milestones": []any{"1.0"}, "prerelease": map[string]any{"enabled": true, "name": "unused"}},
+			version:  "nightly-rc",
+			wantName: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := applyPrereleaseProfile(tt.raw, tt.version)
+			cfg := (&GitLabPlugin{}).parseConfig(got)
+			if cfg.Name != tt.wantName || cfg.Banner != tt.wantBanner || cfg.PackageName != tt.wantPackage {
+				t.Errorf("got name %q banner %q package %q, want %q %q %q", cfg.Name, cfg.Banner, cfg.PackageName, tt.wantName, tt.wantBanner, tt.wantPackage)
+			}
+			if (cfg.Prerelease != nil) != tt.wantProfile {
+				t.Errorf("got profile %v, want applied: %v", cfg.Prerelease, tt.wantProfile)
+			}
+			if len(cfg.Milestones) == 0 {
+				t.Errorf("milestones were dropped: %v", got)
+			}
+		})
+	}
+
+	// The shared configuration is not modified
+	if raw["name"] != "Acme {version}" || raw["milestones"] == nil {
+		t.Errorf("raw config was modified: %v", raw)
+	}
+}
+
+func TestExecutePrerelease(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "app.tar.gz"), []byte("app"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdirForTest(t, tmpDir)
+
+	tests := []struct {
+		name           string
+		version        string
+		wantName       string
+		wantBanner     bool
+		wantReleasedAt any
+	}{
+		{
+			name:     "GA release",
+			version:  "1.4.0",
+			wantName: "Acme 1.4.0",
+		},
+		{
+			name:           "release candidate",
+			version:        "1.4.0-rc.1",
+			wantName:       "Acme 1.4.0-rc.1 (pre-release)",
+			wantBanner:     true,
+			wantReleasedAt: "2026-01-01T23:59:59Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var release map[string]any
+			var uploads []string
+			server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				switch {
+				case r.Method == http.MethodPut && contains(r.URL.Path, "/packages/generic/"):
+					uploads = append(uploads, r.URL.Path)
+					w.WriteHeader(http.StatusCreated)
+					_, _ = w.Write([]byte(`{}`))
+				case r.Method == http.MethodPost && contains(r.URL.Path, "/assets/links"):
+					w.WriteHeader(http.StatusCreated)
+					_, _ = w.Write([]byte(`{"id": 1}`))
+				case r.Method == http.MethodGet && contains(r.URL.Path, "/releases/permalink/latest"):
+					_, _ = w.Write([]byte(`{"tag_name": "v1.3.0", "released_at": "2026-01-02T00:00:00Z"}`))
+				case r.Method == http.MethodPost && contains(r.URL.Path, "/releases"):
+					_ = json.NewDecoder(r.Body).Decode(&release)
+					w.WriteHeader(http.StatusCreated)
+					_ = json.NewEncoder(w).Encode(map[string]any{"tag_name": release["tag_name"], "name": release["name"]})
+				default:
+					http.NotFound(w, r)
+				}
+			})
+
+			p := &GitLabPlugin{}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook: plugin.HookPostPublish,
+				Config: map[string]any{
+					"token":      "glpat-test",
+					"base_url":   server.URL,
+					"project_id": "acme/app",
+					"name":       "Acme {version}",
+					"milestones": []any{"1.4"},
+					"assets":     []any{"app.tar.gz"},
+					"prerelease": map[string]any{"enabled": true},
+				},
+				Context: plugin.ReleaseContext{Version: tt.version, TagName: "v" + tt.version, ReleaseNotes: "Notes"},
+			})
+			if err != nil {
+				t.Fatalf("Execute returned error: %v", err)
+			}
+			if !resp.Success {
+				t.Fatalf("expected success, got %+v", resp)
+			}
+
+			if release["name"] != tt.wantName {
+				t.Errorf("expected name %q, got %v", tt.wantName, release["name"])
+			}
+			description, _ := release["description"].(string)
+			if got := contains(description, "**Pre-release:** "+tt.version); got != tt.wantBanner {
+				t.Errorf("banner present = %v, want %v: %q", got, tt.wantBanner, description)
+			}
+			if !contains(description, "Notes") {
+				t.Errorf("expected release notes in description, got %q", description)
+			}
+			if release["released_at"] != tt.wantReleasedAt {
+				t.Errorf("expected released_at %v, got %v", tt.wantReleasedAt, release["released_at"])
+			}
+			if _, ok := release["milestones"]; !ok {
+				t.Errorf("expected milestones, got %v", release)
+			}
+			if len(uploads) != 1 || !contains(uploads[0], "/packages/generic/release-assets/") {
+				t.Errorf("expected upload to the release-assets package, got %v", uploads)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// In-toto and SLSA identifiers used in provenance documents.
+const (
+	inTotoStatementType = "https://in-toto.io/Statement/v1"
+	slsaPredicateType   = "https://slsa.dev/provenance/v1"
+	provenanceBuildType = "https://github.com/relicta-tech/plugin-gitlab/release/v1"
+	provenanceBuilderID = "https://github.com/relicta-tech/plugin-gitlab"
+)
+
+// provenanceStatement is an in-toto statement carrying a SLSA v1 provenance predicate.
+type provenanceStatement struct {
+	Type          string              `json:"_type"`
+	Subject       []provenanceSubject `json:"subject"`
+	PredicateType string              `json:"predicateType"`
+	Predicate     provenancePredicate `json:"predicate"`
+}
+
+// provenanceSubject identifies a released artifact by digest.
+type provenanceSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+type provenancePredicate struct {
+	BuildDefinition provenanceBuildDefinition `json:"buildDefinition"`
+	RunDetails      provenanceRunDetails      `json:"runDetails"`
+}
+
+type provenanceBuildDefinition struct {
+	BuildType            string                         `json:"buildType"`
+	ExternalParameters   map[string]string              `json:"externalParameters"`
+	InternalParameters   map[string]string              `json:"internalParameters,omitempty"`
+	ResolvedDependencies []provenanceResourceDescriptor `json:"resolvedDependencies,omitempty"`
+}
+
+type provenanceResourceDescriptor struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest"`
+}
+
+type provenanceRunDetails struct {
+	Builder  provenanceBuilder  `json:"builder"`
+	Metadata provenanceMetadata `json:"metadata"`
+}
+
+type provenanceBuilder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version,omitempty"`
+}
+
+type provenanceMetadata struct {
+	InvocationID string `json:"invocationId,omitempty"`
+	FinishedOn   string `json:"finishedOn"`
+}
+
+// envValue returns a variable from the release environment, falling back to
+// the process environment.
+func envValue(releaseCtx plugin.ReleaseContext, key string) string {
+	if v := releaseCtx.Environment[key]; v != "" {
+		return v
+	}
+	return os.Getenv(key)
+}
+
+// buildProvenance describes how the release artifacts were produced. Artifacts
+// without a sha256 checksum cannot be referenced and are skipped.
+func buildProvenance(releaseCtx plugin.ReleaseContext, artifacts []plugin.Artifact, finishedOn time.Time) *provenanceStatement {
+	statement := &provenanceStatement{
+		Type:          inTotoStatementType,
+		Subject:       []provenanceSubject{},
+		PredicateType: slsaPredicateType,
+	}
+
+	for _, artifact := range artifacts {
+		digest, ok := strings.CutPrefix(artifact.Checksum, "sha256:")
+		if !ok || digest == "" {
+			continue
+		}
+		statement.Subject = append(statement.Subject, provenanceSubject{
+			Name:   artifact.Name,
+			Digest: map[string]string{"sha256": digest},
+		})
+	}
+
+	external := map[string]string{
+		"repository": releaseCtx.RepositoryURL,
+		"ref":        releaseCtx.Branch,
+		"tag":        releaseCtx.TagName,
+		"version":    releaseCtx.Version,
+	}
+	internal := map[string]string{}
+	for key, env := range map[string]string{
+		"pipeline_id":  "CI_PIPELINE_ID",
+		"pipeline_url": "CI_PIPELINE_URL",
+		"job_id":       "CI_JOB_ID",
+		"runner_id":    "CI_RUNNER_ID",
+	} {
+		if v := envValue(releaseCtx, env); v != "" {
+			internal[key] = v
+		}
+	}
+
+	build := &statement.Predicate.BuildDefinition
+	build.BuildType = provenanceBuildType
+	build.ExternalParameters = external
+	build.InternalParameters = internal
+	if releaseCtx.CommitSHA != "" {
+		uri := "git+" + releaseCtx.RepositoryURL
+		if releaseCtx.TagName != "" {
+			uri += "@refs/tags/" + releaseCtx.TagName
+		}
+		build.ResolvedDependencies = []provenanceResourceDescriptor{{
+			URI:    uri,
+			Digest: map[string]string{"gitCommit": releaseCtx.CommitSHA},
+		}}
+	}
+
+	run := &statement.Predicate.RunDetails
+	run.Builder.ID = provenanceBuilderID
+	if server := envValue(releaseCtx, "CI_SERVER_URL"); server != "" {
+		run.Builder.ID = server
+		if version := envValue(releaseCtx, "CI_SERVER_VERSION"); version != "" {
+			run.Builder.Version = map[string]string{"gitlab": version}
+		}
+	}
+	run.Metadata.InvocationID = envValue(releaseCtx, "CI_JOB_URL")
+	run.Metadata.FinishedOn = finishedOn.UTC().Format(time.RFC3339)
+
+	return statement
+}
+
+// attachProvenance uploads a provenance document for the release artifacts to
+// the generic package and links it from the release.
+func (p *GitLabPlugin) attachProvenance(ctx context.Context, client *gitlab.Client, projectID, packageName string, releaseCtx plugin.ReleaseContext, artifacts []plugin.Artifact) (*plugin.Artifact, error) {
+	statement := buildProvenance(releaseCtx, artifacts, time.Now())
+	data, err := json.MarshalIndent(statement, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode provenance: %w", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "relicta-gitlab-provenance-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	fileName := "provenance.intoto.json"
+	path := filepath.Join(tmpDir, fileName)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write provenance: %w", err)
+	}
+
+	artifact, err := p.publishGenericFile(ctx, client, projectID, packageName, releaseCtx.TagName, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload provenance: %w", err)
+	}
+	artifact.Type = "provenance"
+
+	link := AssetLink{
+		Name:     "SLSA provenance",
+		URL:      genericPackageURL(client, projectID, packageName, releaseCtx.TagName, fileName),
+		FilePath: "/" + fileName,
+		LinkType: "other",
+	}
+	if err := p.createAssetLink(ctx, client, projectID, releaseCtx.TagName, link); err != nil {
+		return nil, fmt.Errorf("failed to link provenance: %w", err)
+	}
+
+	return artifact, nil
+}
+
+// collectReleaseEvidence asks GitLab to snapshot evidence for the release.
+func collectReleaseEvidence(ctx context.Context, client *gitlab.Client, projectID, tagName string) error {
+	path := fmt.Sprintf("projects/%s/releases/%s/evidence", gitlab.PathEscape(projectID), gitlab.PathEscape(tagName))
+	req, err := client.NewRequest(http.MethodPost, path, nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return err
+	}
+	_, err = client.Do(req, nil)
+	return err
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestBuildProvenance(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{
+		Version:       "1.2.0",
+		TagName:       "v1.2.0",
+		Branch:        "main",
+		CommitSHA:     "abc123",
+		RepositoryURL: "https://gitlab.com/group/project",
+		Environment: map[string]string{
+			"CI_PIPELINE_ID":    "4242",
+			"CI_JOB_URL":        "https://gitlab.com/group/project/-/jobs/99",
+			"CI_SERVER_URL":     "https://gitlab.com",
+			"CI_SERVER_VERSION": "17.5.0",
+		},
+	}
+	artifacts := []plugin.Artifact{
+		{Name: "app.tar.gz", Checksum: "sha256:aaaa"},
+		{Name: "no-checksum.txt"},
+		{Name: "api:1.2.0", Checksum: "sha256:bbbb"},
+	}
+	finished := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	statement := buildProvenance(releaseCtx, artifacts, finished)
+
+	if statement.Type != inTotoStatementType || statement.PredicateType != slsaPredicateType {
+		t.Errorf("unexpected statement types %q %q", statement.Type, statement.PredicateType)
+	}
+	if len(statement.Subject) != 2 || statement.Subject[0].Digest["sha256"] != "aaaa" || statement.Subject[1].Name != "api:1.2.0" {
+		t.Errorf("unexpected subjects %+v", statement.Subject)
+	}
+
+	build := statement.Predicate.BuildDefinition
+	if build.ExternalParameters["tag"] != "v1.2.0" || build.ExternalParameters["ref"] != "main" {
+		t.Errorf("unexpected external parameters %v", build.ExternalParameters)
+	}
+	if build.InternalParameters["pipeline_id"] != "4242" {
+		t.Errorf("expected pipeline ID, got %v", build.InternalParameters)
+	}
+	if len(build.ResolvedDependencies) != 1 || build.ResolvedDependencies[0].Digest["gitCommit"] != "abc123" ||
+		build.ResolvedDependencies[0].URI != "git+https://gitlab.com/group/project@refs/tags/v1.2.0" {
+		t.Errorf("unexpected dependencies %+v", build.ResolvedDependencies)
+	}
+
+	run := statement.Predicate.RunDetails
+	if run.Builder.ID != "https://gitlab.com" || run.Builder.Version["gitlab"] != "17.5.0" {
+		t.Errorf("unexpected builder %+v", run.Builder)
+	}
+	if run.Metadata.InvocationID != "https://gitlab.com/group/project/-/jobs/99" || run.Metadata.FinishedOn != "2025-03-01T12:00:00Z" {
+		t.Errorf("unexpected metadata %+v", run.Metadata)
+	}
+}
+
+func TestCreateReleaseWithProvenanceAndEvidence(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+
+	tmpDir := t.TempDir()
+	chdirForTest(t, tmpDir)
+	_ = os.WriteFile("app.tar.gz", []byte("app-bytes"), 0644)
+	appDigest := sha256.Sum256([]byte("app-bytes"))
+
+	var mu sync.Mutex
+	var calls []string
+	var provenance provenanceStatement
+	var linkRequests []map[string]any
+
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, r.Method+" "+r.URL.EscapedPath())
+
+		switch {
+		case r.URL.Path == "/api/v4/projects/group/project/releases" && r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(gitlab.Release{TagName: "v1.2.0", Name: "Release 1.2.0"})
+		case contains(r.URL.Path, "/packages/generic/release-assets/v1.2.0/provenance.intoto.json"):
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &provenance)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		case contains(r.URL.Path, "/packages/generic/"):
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		case contains(r.URL.Path, "/assets/links") && r.Method == http.MethodPost:
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			linkRequests = append(linkRequests, body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 1}`))
+		case contains(r.URL.Path, "/releases/v1.2.0/evidence") && r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	p := &GitLabPlugin{}
+	cfg := &Config{
+		Token:           "glpat-test",
+		ProjectID:       "group/project",
+		BaseURL:         server.URL,
+		Assets:          []Asset{{Path: "app.tar.gz"}},
+		Provenance:      true,
+		CollectEvidence: true,
+	}
+
+	resp, err := p.createRelease(context.Background(), cfg, plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0", CommitSHA: "abc123"}, false)
+	if err != nil {
+		t.Fatalf("createRelease returned error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	if len(resp.Artifacts) != 2 || resp.Artifacts[1].Type != "provenance" {
+		t.Errorf("expected asset and provenance artifacts, got %+v", resp.Artifacts)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(provenance.Subject) != 1 || provenance.Subject[0].Digest["sha256"] != hex.EncodeToString(appDigest[:]) {
+		t.Errorf("expected provenance subject for app.tar.gz, got %+v", provenance.Subject)
+	}
+	if len(linkRequests) != 1 || linkRequests[0]["name"] != "SLSA provenance" || linkRequests[0]["direct_asset_path"] != "/provenance.intoto.json" {
+		t.Errorf("unexpected provenance link %v", linkRequests)
+	}
+	// Evidence is collected once all links exist
+	lastLink, evidence := -1, -1
+	for i, call := range calls {
+		switch {
+		case call == "POST /api/v4/projects/group%2Fproject/releases/v1%2E2%2E0/assets/links":
+			lastLink = i
+		case call == "POST /api/v4/projects/group%2Fproject/releases/v1%2E2%2E0/evidence":
+			evidence = i
+		}
+	}
+	if lastLink < 0 || evidence < lastLink {
+		t.Errorf("expected evidence collection after the last link, got %v", calls)
+	}
+}
+
+func TestCreateReleaseEvidenceFailure(t *testing.T) {
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case contains(r.URL.Path, "/evidence"):
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message": "403 Forbidden"}`))
+		case contains(r.URL.Path, "/releases") && r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(gitlab.Release{TagName: "v1.2.0"})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	p := &GitLabPlugin{}
+	cfg := &Config{Token: "glpat-test", ProjectID: "group/project", BaseURL: server.URL, CollectEvidence: true}
+
+	resp, err := p.createRelease(context.Background(), cfg, plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"}, false)
+	if err != nil {
+		t.Fatalf("createRelease returned error: %v", err)
+	}
+	if resp.Success || !contains(resp.Error, "evidence collection failed") {
+		t.Errorf("expected evidence failure, got %+v", resp)
+	}
+}
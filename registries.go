@@ -0,0 +1,415 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/md5"  //nolint:gosec // GitLab's PyPI API requires an MD5 digest
+	"crypto/sha1" //nolint:gosec // npm dist metadata requires a SHA-1 shasum
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// Package registries an asset can be published to.
+const (
+	registryGeneric = "generic"
+	registryMaven   = "maven"
+	registryNPM     = "npm"
+	registryPyPI    = "pypi"
+)
+
+// validRegistries lists the accepted values for an asset's registry option.
+var validRegistries = map[string]bool{
+	registryGeneric:   true,
+	registryMaven:     true,
+	registryNPM:       true,
+	registryPyPI:      true,
+	registryContainer: true,
+	registryHelm:      true,
+	registryTerraform: true,
+	registryDebian:    true,
+	registryRPM:       true,
+}
+
+// mavenPOM holds the coordinates read from a Maven POM file.
+type mavenPOM struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Parent     struct {
+		GroupID string `xml:"groupId"`
+		Version string `xml:"version"`
+	} `xml:"parent"`
+}
+
+// parseMavenPOM reads the Maven coordinates from a POM file, falling back to
+// the parent's group and version and finally to defaultVersion.
+func parseMavenPOM(path, defaultVersion string) (*mavenPOM, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pom: %w", err)
+	}
+
+	var pom mavenPOM
+	if err := xml.Unmarshal(data, &pom); err != nil {
+		return nil, fmt.Errorf("failed to parse pom: %w", err)
+	}
+
+	if pom.GroupID == "" {
+		pom.GroupID = pom.Parent.GroupID
+	}
+	if pom.Version == "" {
+		pom.Version = pom.Parent.Version
+	}
+	// Unresolved properties such as ${revision} are replaced by the release version
+	if pom.Version == "" || strings.Contains(pom.Version, "${") {
+		pom.Version = defaultVersion
+	}
+
+	if pom.GroupID == "" || pom.ArtifactID == "" {
+		return nil, fmt.Errorf("pom must declare groupId and artifactId")
+	}
+
+	return &pom, nil
+}
+
+// publishMavenPackage uploads a jar and its POM to the project's Maven repository.
+func (p *GitLabPlugin) publishMavenPackage(ctx context.Context, client *gitlab.Client, projectID string, releaseCtx plugin.ReleaseContext, asset Asset) (*publishedAsset, error) {
+	jarPath, err := validateAssetFile(asset.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	pomAsset := asset.Pom
+	if pomAsset == "" {
+		pomAsset = strings.TrimSuffix(asset.Path, filepath.Ext(asset.Path)) + ".pom"
+	}
+	pomPath, err := validateAssetFile(pomAsset)
+	if err != nil {
+		return nil, fmt.Errorf("maven package requires a pom: %w", err)
+	}
+
+	pom, err := parseMavenPOM(pomPath, releaseCtx.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := strings.Split(pom.GroupID, ".")
+	segments = append(segments, pom.ArtifactID, pom.Version)
+	for i, segment := range segments {
+		segments[i] = gitlab.PathEscape(segment)
+	}
+	packagePath := fmt.Sprintf("projects/%s/packages/maven/%s", gitlab.PathEscape(projectID), strings.Join(segments, "/"))
+
+	baseName := fmt.Sprintf("%s-%s", pom.ArtifactID, pom.Version)
+	files := []struct {
+		local, name string
+	}{
+		{jarPath, baseName + filepath.Ext(jarPath)},
+		{pomPath, baseName + ".pom"},
+	}
+
+	published := &publishedAsset{}
+	for _, f := range files {
+		artifact, err := putPackageFile(ctx, client, packagePath+"/"+gitlab.PathEscape(f.name), nil, f.local, f.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload maven file %s: %w", f.name, err)
+		}
+		artifact.Type = "maven_package"
+		published.artifacts = append(published.artifacts, *artifact)
+	}
+
+	published.link = &AssetLink{
+		Name:     fmt.Sprintf("%s:%s:%s (Maven)", pom.GroupID, pom.ArtifactID, pom.Version),
+		URL:      client.BaseURL().String() + packagePath + "/" + gitlab.PathEscape(files[0].name),
+		LinkType: "package",
+	}
+	return published, nil
+}
+
+// npm dist-tags that packages are published under by default.
+const (
+	npmDistTagLatest = "latest"
+	npmDistTagNext   = "next"
+)
+
+// npmDistTag returns the dist-tag to publish a package version under. An
+// explicit tag wins; otherwise prereleases go to "next" so that installs
+// without a version keep getting the latest stable release.
+func npmDistTag(configured, version string) string {
+	if configured != "" {
+		return configured
+	}
+	if isPrerelease(version) {
+		return npmDistTagNext
+	}
+	return npmDistTagLatest
+}
+
+// npmPackageJSON holds the fields the plugin needs from a package.json manifest.
+type npmPackageJSON struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// readNPMManifest extracts package/package.json from an npm tarball. The raw
+// manifest is returned alongside the parsed name and version so that all of
+// its metadata can be forwarded to the registry.
+func readNPMManifest(tarballPath string) (map[string]any, *npmPackageJSON, error) {
+	f, err := os.Open(tarballPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("npm package is not a gzip tarball: %w", err)
+	}
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read npm tarball: %w", err)
+		}
+		if hdr.Name != "package/package.json" {
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, nil, err
+		}
+		var raw map[string]any
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, nil, fmt.Errorf("invalid package.json: %w", err)
+		}
+		var pkg npmPackageJSON
+		if err := json.Unmarshal(data, &pkg); err != nil {
+			return nil, nil, fmt.Errorf("invalid package.json: %w", err)
+		}
+		if pkg.Name == "" || pkg.Version == "" {
+			return nil, nil, fmt.Errorf("package.json must declare name and version")
+		}
+		return raw, &pkg, nil
+	}
+
+	return nil, nil, fmt.Errorf("package/package.json not found in npm tarball")
+}
+
+// publishNPMPackage publishes an npm tarball to the project's npm registry.
+func (p *GitLabPlugin) publishNPMPackage(ctx context.Context, client *gitlab.Client, projectID string, asset Asset) (*publishedAsset, error) {
+	tarballPath, err := validateAssetFile(asset.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, pkg, err := readNPMManifest(tarballPath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(tarballPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read npm tarball: %w", err)
+	}
+
+	// Scoped packages are stored as <name>-<version>.tgz without the scope
+	baseName := pkg.Name
+	if i := strings.LastIndex(baseName, "/"); i >= 0 {
+		baseName = baseName[i+1:]
+	}
+	fileName := fmt.Sprintf("%s-%s.tgz", baseName, pkg.Version)
+
+	packagePath := fmt.Sprintf("projects/%s/packages/npm/%s", gitlab.PathEscape(projectID), strings.ReplaceAll(pkg.Name, "/", "%2F"))
+	tarballURL := fmt.Sprintf("%s%s/-/%s", client.BaseURL().String(), packagePath, fileName)
+
+	shasum := sha1.Sum(data) //nolint:gosec // required by npm dist metadata
+	integrity := sha512.Sum512(data)
+
+	manifest["_id"] = pkg.Name + "@" + pkg.Version
+	manifest["dist"] = map[string]any{
+		"shasum":    hex.EncodeToString(shasum[:]),
+		"integrity": "sha512-" + base64.StdEncoding.EncodeToString(integrity[:]),
+		"tarball":   tarballURL,
+	}
+
+	body := map[string]any{
+		"_id":       pkg.Name,
+		"name":      pkg.Name,
+		"dist-tags": map[string]string{npmDistTag(asset.DistTag, pkg.Version): pkg.Version},
+		"versions":  map[string]any{pkg.Version: manifest},
+		"_attachments": map[string]any{
+			fileName: map[string]any{
+				"content_type": "application/octet-stream",
+				"data":         base64.StdEncoding.EncodeToString(data),
+				"length":       len(data),
+			},
+		},
+	}
+
+	req, err := client.NewRequest(http.MethodPut, packagePath, body, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := client.Do(req, nil); err != nil {
+		return nil, fmt.Errorf("failed to publish npm package %s: %w", pkg.Name, err)
+	}
+
+	digest := sha256.Sum256(data)
+	return &publishedAsset{
+		artifacts: []plugin.Artifact{{
+			Name:     fileName,
+			Path:     tarballURL,
+			Type:     "npm_package",
+			Size:     int64(len(data)),
+			Checksum: "sha256:" + hex.EncodeToString(digest[:]),
+		}},
+		link: &AssetLink{
+			Name:     fmt.Sprintf("%s@%s (npm)", pkg.Name, pkg.Version),
+			URL:      tarballURL,
+			LinkType: "package",
+		},
+	}, nil
+}
+
+// parsePythonDistribution derives the project name and version from a wheel
+// or sdist file name as defined by the Python packaging specifications.
+func parsePythonDistribution(fileName string) (name, version string, err error) {
+	switch {
+	case strings.HasSuffix(fileName, ".whl"):
+		// {distribution}-{version}(-{build})?-{python}-{abi}-{platform}.whl
+		parts := strings.Split(strings.TrimSuffix(fileName, ".whl"), "-")
+		if len(parts) < 5 {
+			return "", "", fmt.Errorf("invalid wheel file name: %s", fileName)
+		}
+		return parts[0], parts[1], nil
+	case strings.HasSuffix(fileName, ".tar.gz"), strings.HasSuffix(fileName, ".zip"):
+		// {name}-{version}.tar.gz
+		base := strings.TrimSuffix(strings.TrimSuffix(fileName, ".tar.gz"), ".zip")
+		i := strings.LastIndex(base, "-")
+		if i <= 0 || i == len(base)-1 {
+			return "", "", fmt.Errorf("invalid sdist file name: %s", fileName)
+		}
+		return base[:i], base[i+1:], nil
+	default:
+		return "", "", fmt.Errorf("unsupported python distribution: %s (expected .whl or .tar.gz)", fileName)
+	}
+}
+
+// pypiUploadOptions holds the form fields sent alongside a PyPI upload.
+type pypiUploadOptions struct {
+	Name         string `url:"name"`
+	Version      string `url:"version"`
+	SHA256Digest string `url:"sha256_digest"`
+	MD5Digest    string `url:"md5_digest"`
+}
+
+// publishPyPIPackage uploads a wheel or sdist to the project's PyPI registry.
+func (p *GitLabPlugin) publishPyPIPackage(ctx context.Context, client *gitlab.Client, projectID string, asset Asset) (*publishedAsset, error) {
+	distPath, err := validateAssetFile(asset.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := filepath.Base(distPath)
+	name, version, err := parsePythonDistribution(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(distPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read python distribution: %w", err)
+	}
+	sha := sha256.Sum256(data)
+	md := md5.Sum(data) //nolint:gosec // required by the PyPI upload API
+	shaHex := hex.EncodeToString(sha[:])
+
+	opts := &pypiUploadOptions{
+		Name:         name,
+		Version:      version,
+		SHA256Digest: shaHex,
+		MD5Digest:    hex.EncodeToString(md[:]),
+	}
+
+	packagePath := fmt.Sprintf("projects/%s/packages/pypi", gitlab.PathEscape(projectID))
+	req, err := client.UploadRequest(http.MethodPost, packagePath, bytes.NewReader(data), fileName, gitlab.UploadType("content"), opts, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := client.Do(req, nil); err != nil {
+		return nil, fmt.Errorf("failed to publish python package %s: %w", fileName, err)
+	}
+
+	fileURL := fmt.Sprintf("%s%s/files/%s/%s", client.BaseURL().String(), packagePath, shaHex, fileName)
+	return &publishedAsset{
+		artifacts: []plugin.Artifact{{
+			Name:     fileName,
+			Path:     fileURL,
+			Type:     "pypi_package",
+			Size:     int64(len(data)),
+			Checksum: "sha256:" + shaHex,
+		}},
+		link: &AssetLink{
+			Name:     fmt.Sprintf("%s %s (PyPI)", name, version),
+			URL:      fileURL,
+			LinkType: "package",
+		},
+	}, nil
+}
+
+// putPackageFile uploads a local file as the raw body of a PUT request to a
+// package registry endpoint, with optional query parameters.
+func putPackageFile(ctx context.Context, client *gitlab.Client, apiPath string, query url.Values, filePath, fileName string) (*plugin.Artifact, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	// Create the request without a body so no JSON encoding takes place,
+	// then attach the file content.
+	req, err := client.NewRequest(http.MethodPut, apiPath, nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if len(query) > 0 {
+		req.URL.RawQuery = query.Encode()
+	}
+	if err := req.SetBody(data); err != nil {
+		return nil, err
+	}
+
+	if _, err := client.Do(req, nil); err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256(data)
+	return &plugin.Artifact{
+		Name:     fileName,
+		Path:     client.BaseURL().String() + apiPath,
+		Size:     int64(len(data)),
+		Checksum: "sha256:" + hex.EncodeToString(digest[:]),
+	}, nil
+}
@@ -0,0 +1,482 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// chdirForTest changes into dir for the duration of the test.
+func chdirForTest(t *testing.T, dir string) {
+	t.Helper()
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("failed to change to %s: %v", dir, err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(origDir)
+	})
+}
+
+// newTestClient creates a GitLab client pointing at a mock server.
+func newTestClient(t *testing.T, server string) *gitlab.Client {
+	t.Helper()
+	client, err := gitlab.NewClient("glpat-test", gitlab.WithBaseURL(server+"/api/v4/"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+// writeTarGz writes a gzip-compressed tarball containing the given files.
+func writeTarGz(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}); err != nil {
+			t.Fatalf("failed to write tar header: %v", err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatalf("failed to write tar content: %v", err)
+		}
+	}
+	_ = tw.Close()
+	_ = gz.Close()
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("failed to write tarball: %v", err)
+	}
+}
+
+func TestParseMavenPOM(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		pom         string
+		wantGroup   string
+		wantVersion string
+		wantError   bool
+	}{
+		{
+			name: "explicit coordinates",
+			pom: `<project><groupId>com.example</groupId><artifactId>lib</artifactId>
+				<version>1.2.3</version></project>`,
+			wantGroup:   "com.example",
+			wantVersion: "1.2.3",
+		},
+		{
+			name: "inherits group and version from parent",
+			pom: `<project><parent><groupId>org.acme</groupId><version>2.0.0</version></parent>
+				<artifactId>child</artifactId></project>`,
+			wantGroup:   "org.acme",
+			wantVersion: "2.0.0",
+		},
+		{
+			name: "unresolved property falls back to release version",
+			pom: `<project><groupId>com.example</groupId><artifactId>lib</artifactId>
+				<version>${revision}</version></project>`,
+			wantGroup:   "com.example",
+			wantVersion: "9.9.9",
+		},
+		{
+			name:      "missing artifactId",
+			pom:       `<project><groupId>com.example</groupId></project>`,
+			wantError: true,
+		},
+		{
+			name:      "invalid xml",
+			pom:       `<project>`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "pom.xml")
+			if err := os.WriteFile(path, []byte(tt.pom), 0644); err != nil {
+				t.Fatalf("failed to write pom: %v", err)
+			}
+
+			pom, err := parseMavenPOM(path, "9.9.9")
+			if tt.wantError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if pom.GroupID != tt.wantGroup {
+				t.Errorf("expected group %q, got %q", tt.wantGroup, pom.GroupID)
+			}
+			if pom.Version != tt.wantVersion {
+				t.Errorf("expected version %q, got %q", tt.wantVersion, pom.Version)
+			}
+		})
+	}
+}
+
+func TestParsePythonDistribution(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		fileName    string
+		wantName    string
+		wantVersion string
+		wantError   bool
+	}{
+		{fileName: "my_pkg-1.2.3-py3-none-any.whl", wantName: "my_pkg", wantVersion: "1.2.3"},
+		{fileName: "my_pkg-1.2.3-1-cp312-cp312-manylinux_2_17_x86_64.whl", wantName: "my_pkg", wantVersion: "1.2.3"},
+		{fileName: "my-pkg-1.2.3.tar.gz", wantName: "my-pkg", wantVersion: "1.2.3"},
+		{fileName: "my_pkg-1.0.0rc1.zip", wantName: "my_pkg", wantVersion: "1.0.0rc1"},
+		{fileName: "broken.whl", wantError: true},
+		{fileName: "noversion.tar.gz", wantError: true},
+		{fileName: "pkg-1.0.0.egg", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fileName, func(t *testing.T) {
+			name, version, err := parsePythonDistribution(tt.fileName)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("expected error, got name=%q version=%q", name, version)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if name != tt.wantName || version != tt.wantVersion {
+				t.Errorf("expected %s %s, got %s %s", tt.wantName, tt.wantVersion, name, version)
+			}
+		})
+	}
+}
+
+func TestReadNPMManifest(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.tgz")
+	writeTarGz(t, valid, map[string]string{
+		"package/index.js":     "module.exports = 1",
+		"package/package.json": `{"name": "@acme/widget", "version": "1.2.3", "license": "MIT"}`,
+	})
+	missing := filepath.Join(dir, "missing.tgz")
+	writeTarGz(t, missing, map[string]string{"package/index.js": ""})
+	noVersion := filepath.Join(dir, "noversion.tgz")
+	writeTarGz(t, noVersion, map[string]string{"package/package.json": `{"name": "x"}`})
+
+	raw, pkg, err := readNPMManifest(valid)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pkg.Name != "@acme/widget" || pkg.Version != "1.2.3" {
+		t.Errorf("unexpected package %+v", pkg)
+	}
+	if raw["license"] != "MIT" {
+		t.Errorf("expected raw manifest to keep license, got %v", raw["license"])
+	}
+
+	if _, _, err := readNPMManifest(missing); err == nil || !contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	if _, _, err := readNPMManifest(noVersion); err == nil {
+		t.Error("expected error for manifest without version")
+	}
+}
+
+// registryRequest records a request received by the mock registry server.
+type registryRequest struct {
+	method      string
+	path        string
+	contentType string
+	body        []byte
+}
+
+// recordingServer returns a mock GitLab server that records every request
+// and answers with 201 Created.
+func recordingServer(t *testing.T) (*gitlab.Client, func() []registryRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []registryRequest
+
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, registryRequest{
+			method:      r.Method,
+			path:        r.URL.EscapedPath(),
+			contentType: r.Header.Get("Content-Type"),
+			body:        body,
+		})
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{}`))
+	})
+
+	return newTestClient(t, server.URL), func() []registryRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]registryRequest(nil), requests...)
+	}
+}
+
+func TestPublishMavenPackage(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+
+	tmpDir := t.TempDir()
+	chdirForTest(t, tmpDir)
+
+	if err := os.MkdirAll("target", 0755); err != nil {
+		t.Fatalf("failed to create target: %v", err)
+	}
+	_ = os.WriteFile("target/app.jar", []byte("jar-bytes"), 0644)
+	_ = os.WriteFile("target/app.pom", []byte(`<project><groupId>com.example.tools</groupId>
+		<artifactId>app</artifactId><version>1.4.0</version></project>`), 0644)
+	_ = os.WriteFile("target/orphan.jar", []byte("jar-bytes"), 0644)
+
+	client, requests := recordingServer(t)
+	p := &GitLabPlugin{}
+	releaseCtx := plugin.ReleaseContext{Version: "1.4.0", TagName: "v1.4.0"}
+
+	published, err := p.uploadReleaseAsset(context.Background(), client, &Config{}, "group/project", releaseCtx, Asset{Path: "target/app.jar", Registry: "maven"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reqs := requests()
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 uploads, got %d", len(reqs))
+	}
+	wantPaths := []string{
+		"/api/v4/projects/group%2Fproject/packages/maven/com/example/tools/app/1%2E4%2E0/app-1%2E4%2E0%2Ejar",
+		"/api/v4/projects/group%2Fproject/packages/maven/com/example/tools/app/1%2E4%2E0/app-1%2E4%2E0%2Epom",
+	}
+	for i, want := range wantPaths {
+		if reqs[i].method != http.MethodPut {
+			t.Errorf("request %d: expected PUT, got %s", i, reqs[i].method)
+		}
+		if reqs[i].path != want {
+			t.Errorf("request %d: expected path %q, got %q", i, want, reqs[i].path)
+		}
+	}
+	if string(reqs[0].body) != "jar-bytes" {
+		t.Errorf("expected raw jar body, got %q", reqs[0].body)
+	}
+
+	if len(published.artifacts) != 2 || published.artifacts[0].Name != "app-1.4.0.jar" {
+		t.Errorf("unexpected artifacts %+v", published.artifacts)
+	}
+	if published.link == nil || published.link.LinkType != "package" {
+		t.Fatalf("expected package link, got %+v", published.link)
+	}
+	if !contains(published.link.Name, "com.example.tools:app:1.4.0") {
+		t.Errorf("unexpected link name %q", published.link.Name)
+	}
+
+	if _, err := p.uploadReleaseAsset(context.Background(), client, &Config{}, "group/project", releaseCtx, Asset{Path: "target/orphan.jar", Registry: "maven"}); err == nil || !contains(err.Error(), "requires a pom") {
+		t.Errorf("expected missing pom error, got %v", err)
+	}
+}
+
+func TestPublishNPMPackage(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+
+	tmpDir := t.TempDir()
+	chdirForTest(t, tmpDir)
+	writeTarGz(t, filepath.Join(tmpDir, "widget-1.2.3.tgz"), map[string]string{
+		"package/package.json": `{"name": "@acme/widget", "version": "1.2.3", "main": "index.js"}`,
+	})
+
+	client, requests := recordingServer(t)
+	p := &GitLabPlugin{}
+
+	published, err := p.uploadReleaseAsset(context.Background(), client, &Config{}, "group/project", plugin.ReleaseContext{TagName: "v1.2.3"}, Asset{Path: "widget-1.2.3.tgz", Registry: "npm"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reqs := requests()
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	if reqs[0].method != http.MethodPut || reqs[0].path != "/api/v4/projects/group%2Fproject/packages/npm/@acme%2Fwidget" {
+		t.Errorf("unexpected request %s %s", reqs[0].method, reqs[0].path)
+	}
+
+	var body struct {
+		Name        string                    `json:"name"`
+		DistTags    map[string]string         `json:"dist-tags"`
+		Versions    map[string]map[string]any `json:"versions"`
+		Attachments map[string]map[string]any `json:"_attachments"`
+	}
+	if err := json.Unmarshal(reqs[0].body, &body); err != nil {
+		t.Fatalf("invalid publish body: %v", err)
+	}
+	if body.Name != "@acme/widget" || body.DistTags["latest"] != "1.2.3" {
+		t.Errorf("unexpected metadata: %+v", body)
+	}
+	version := body.Versions["1.2.3"]
+	if version["main"] != "index.js" {
+		t.Errorf("expected manifest fields to be forwarded, got %v", version)
+	}
+	dist, _ := version["dist"].(map[string]any)
+	if !contains(dist["tarball"].(string), "/packages/npm/@acme%2Fwidget/-/widget-1.2.3.tgz") {
+		t.Errorf("unexpected tarball URL %v", dist["tarball"])
+	}
+	if !contains(dist["integrity"].(string), "sha512-") {
+		t.Errorf("expected sha512 integrity, got %v", dist["integrity"])
+	}
+	if _, ok := body.Attachments["widget-1.2.3.tgz"]; !ok {
+		t.Errorf("expected attachment widget-1.2.3.tgz, got %v", body.Attachments)
+	}
+
+	if published.link == nil || published.link.URL != dist["tarball"] {
+		t.Errorf("expected link to tarball, got %+v", published.link)
+	}
+}
+
+func TestNPMDistTag(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		configured string
+		version    string
+		want       string
+	}{
+		{name: "stable version", version: "1.2.3", want: "latest"},
+		{name: "prerelease version", version: "1.3.0-rc.1", want: "next"},
+		{name: "configured tag", configured: "beta", version: "1.3.0-rc.1", want: "beta"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := npmDistTag(tt.configured, tt.version); got != tt.want {
+				t.Errorf("npmDistTag(%q, %q) = %q, want %q", tt.configured, tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublishPyPIPackage(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+
+	tmpDir := t.TempDir()
+	chdirForTest(t, tmpDir)
+	_ = os.WriteFile("acme_tool-2.1.0-py3-none-any.whl", []byte("wheel-bytes"), 0644)
+
+	client, requests := recordingServer(t)
+	p := &GitLabPlugin{}
+
+	published, err := p.uploadReleaseAsset(context.Background(), client, &Config{}, "group/project", plugin.ReleaseContext{TagName: "v2.1.0"}, Asset{Path: "acme_tool-2.1.0-py3-none-any.whl", Registry: "pypi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reqs := requests()
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	if reqs[0].method != http.MethodPost || reqs[0].path != "/api/v4/projects/group%2Fproject/packages/pypi" {
+		t.Errorf("unexpected request %s %s", reqs[0].method, reqs[0].path)
+	}
+	if !contains(reqs[0].contentType, "multipart/form-data") {
+		t.Errorf("expected multipart upload, got %q", reqs[0].contentType)
+	}
+	for _, field := range []string{`name="content"`, `name="name"`, "acme_tool", `name="version"`, "2.1.0", `name="sha256_digest"`, `name="md5_digest"`} {
+		if !contains(string(reqs[0].body), field) {
+			t.Errorf("expected multipart body to contain %q", field)
+		}
+	}
+
+	if published.artifacts[0].Type != "pypi_package" {
+		t.Errorf("unexpected artifact type %q", published.artifacts[0].Type)
+	}
+	if published.link == nil || !contains(published.link.URL, "/packages/pypi/files/") {
+		t.Errorf("expected link to pypi file, got %+v", published.link)
+	}
+}
+
+func TestCreateReleaseLinksRegistryPackages(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+
+	tmpDir := t.TempDir()
+	chdirForTest(t, tmpDir)
+	_ = os.WriteFile("acme_tool-2.1.0.tar.gz", []byte("sdist"), 0644)
+
+	var mu sync.Mutex
+	var linkRequests []map[string]any
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case contains(r.URL.Path, "/assets/links") && r.Method == http.MethodPost:
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			mu.Lock()
+			linkRequests = append(linkRequests, body)
+			mu.Unlock()
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 1}`))
+		case contains(r.URL.Path, "/releases") && r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(gitlab.Release{TagName: "v2.1.0", Name: "Release 2.1.0"})
+		case contains(r.URL.Path, "/packages/pypi"):
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	p := &GitLabPlugin{}
+	cfg := &Config{
+		Token:     "glpat-test",
+		ProjectID: "group/project",
+		BaseURL:   server.URL,
+		Assets:    []Asset{{Path: "acme_tool-2.1.0.tar.gz", Registry: "pypi"}},
+	}
+
+	resp, err := p.createRelease(context.Background(), cfg, plugin.ReleaseContext{Version: "2.1.0", TagName: "v2.1.0"}, false)
+	if err != nil {
+		t.Fatalf("createRelease returned error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	if len(resp.Artifacts) != 1 {
+		t.Errorf("expected 1 artifact, got %d", len(resp.Artifacts))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(linkRequests) != 1 {
+		t.Fatalf("expected 1 release link, got %d", len(linkRequests))
+	}
+	if linkRequests[0]["link_type"] != "package" {
+		t.Errorf("expected package link type, got %v", linkRequests[0]["link_type"])
+	}
+}
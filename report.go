@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// releaseReport is the machine-readable summary written to report_file.
+type releaseReport struct {
+	Success    bool           `json:"success"`
+	Error      string         `json:"error,omitempty"`
+	Project    string         `json:"project"`
+	GitLabURL  string         `json:"gitlab_url"`
+	TagName    string         `json:"tag_name"`
+	Version    string         `json:"version,omitempty"`
+	Name       string         `json:"name,omitempty"`
+	ReleaseURL string         `json:"release_url,omitempty"`
+	Milestones []string       `json:"milestones"`
+	Assets     []reportAsset  `json:"assets"`
+	Links      []releaseLink  `json:"links"`
+	Warnings   []string       `json:"warnings"`
+	Timings    reportTimings  `json:"timings"`
+	Outputs    map[string]any `json:"outputs,omitempty"`
+}
+
+// reportAsset describes a published file.
+type reportAsset struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	URL    string `json:"url,omitempty"`
+	Size   int64  `json:"size,omitempty"`
+	Digest string `json:"digest,omitempty"`
+}
+
+// reportTimings records when publishing started and how long each phase took.
+type reportTimings struct {
+	StartedAt       time.Time `json:"started_at"`
+	FinishedAt      time.Time `json:"finished_at"`
+	TotalMS         int64     `json:"total_ms"`
+	CreateReleaseMS int64     `json:"create_release_ms"`
+	AssetsMS        int64     `json:"assets_ms"`
+}
+
+// newReleaseReport starts a report for the release of releaseCtx.
+func newReleaseReport(cfg *Config, releaseCtx plugin.ReleaseContext, now time.Time) *releaseReport {
+	return &releaseReport{
+		GitLabURL:  normalizeBaseURL(cfg.BaseURL),
+		TagName:    releaseCtx.TagName,
+		Version:    releaseCtx.Version,
+		Milestones: append([]string{}, cfg.Milestones...),
+		Assets:     []reportAsset{},
+		Links:      []releaseLink{},
+		Warnings:   []string{},
+		Timings:    reportTimings{StartedAt: now.UTC()},
+	}
+}
+
+// addArtifacts records published files with their URL. Files in the generic
+// package, such as assets, the SBOM and provenance, get their download URL,
+// container images their registry reference and other package registry
+// files the URL they were published to.
+func (r *releaseReport) addArtifacts(client *gitlab.Client, cfg *Config, projectID string, artifacts []plugin.Artifact) {
+	for _, artifact := range artifacts {
+		asset := reportAsset{
+			Name:   artifact.Name,
+			Type:   artifact.Type,
+			Size:   artifact.Size,
+			Digest: artifact.Checksum,
+		}
+		switch {
+		case artifact.Type == "generic_package" || artifact.Type == "sbom" || artifact.Type == "provenance":
+			asset.URL = genericPackageURL(client, projectID, genericPackageName(cfg), r.TagName, artifact.Name)
+		case artifact.Type == "container_image":
+			if registryURL, err := containerRegistryURL(cfg); err == nil {
+				asset.URL = fmt.Sprintf("%s://%s", registryURL.Scheme, artifact.Path)
+			}
+		case strings.HasPrefix(artifact.Path, "https://") || strings.HasPrefix(artifact.Path, "http://"):
+			asset.URL = artifact.Path
+		}
+		r.Assets = append(r.Assets, asset)
+	}
+}
+
+// finish completes the report from the publishing result.
+func (r *releaseReport) finish(resp *plugin.ExecuteResponse, now time.Time) {
+	r.Success = resp.Success
+	r.Error = resp.Error
+	r.Outputs = resp.Outputs
+	if url, ok := resp.Outputs["release_url"].(string); ok {
+		r.ReleaseURL = url
+	}
+	if warnings, ok := resp.Outputs["warnings"].([]string); ok {
+		r.Warnings = warnings
+	}
+	r.Timings.FinishedAt = now.UTC()
+	r.Timings.TotalMS = now.Sub(r.Timings.StartedAt).Milliseconds()
+}
+
+// writeReport writes the report as indented JSON, creating parent directories.
+func writeReport(path string, report *releaseReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestExecuteReportFile(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "app.tar.gz"), []byte("app"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdirForTest(t, tmpDir)
+
+	tests := []struct {
+		name        string
+		releaseCode int
+		wantSuccess bool
+	}{
+		{name: "published release", releaseCode: http.StatusCreated, wantSuccess: true},
+		{name: "failed release", releaseCode: http.StatusConflict, wantSuccess: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPut && contains(r.URL.Path, "/packages/generic/"):
+					w.WriteHeader(http.StatusCreated)
+					_, _ = w.Write([]byte(`{}`))
+				case r.Method == http.MethodGet && contains(r.URL.Path, "/packages"):
+					_, _ = w.Write([]byte(`[]`))
+				case r.Method == http.MethodGet && contains(r.URL.Path, "/assets/links"):
+					_, _ = w.Write([]byte(`[{"id": 7, "name": "Docs", "url": "https://docs.example.com", "link_type": "runbook"}]`))
+				case r.Method == http.MethodPost && contains(r.URL.Path, "/releases"):
+					w.WriteHeader(tt.releaseCode)
+					if tt.releaseCode != http.StatusCreated {
+						_, _ = w.Write([]byte(`{"message": "Release already exists"}`))
+						return
+					}
+					_, _ = w.Write([]byte(`{"tag_name": "v1.4.0", "name": "Release 1.4.0"}`))
+				default:
+					http.NotFound(w, r)
+				}
+			})
+
+			p := &GitLabPlugin{}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook: plugin.HookPostPublish,
+				Config: map[string]any{
+					"token":       "glpat-test",
+					"base_url":    server.URL,
+					"project_id":  "acme/app",
+					"milestones":  []any{"1.4"},
+					"assets":      []any{"app.tar.gz", "missing.zip"},
+					"report_file": "reports/release-{version}.json",
+				},
+				Context: plugin.ReleaseContext{Version: "1.4.0", TagName: "v1.4.0"},
+			})
+			if err != nil {
+				t.Fatalf("Execute returned error: %v", err)
+			}
+			if resp.Success != tt.wantSuccess {
+				t.Fatalf("unexpected response %+v", resp)
+			}
+
+			data, err := os.ReadFile(filepath.Join(tmpDir, "reports", "release-1.4.0.json"))
+			if err != nil {
+				t.Fatalf("report not written: %v", err)
+			}
+			var report releaseReport
+			if err := json.Unmarshal(data, &report); err != nil {
+				t.Fatalf("invalid report: %v\n%s", err, data)
+			}
+
+			if report.Success != tt.wantSuccess || report.Project != "acme/app" || report.TagName != "v1.4.0" || report.Version != "1.4.0" {
+				t.Errorf("unexpected report header %+v", report)
+			}
+			if len(report.Milestones) != 1 || report.Milestones[0] != "1.4" {
+				t.Errorf("unexpected milestones %v", report.Milestones)
+			}
+			if report.Timings.StartedAt.IsZero() || report.Timings.FinishedAt.Before(report.Timings.StartedAt) {
+				t.Errorf("unexpected timings %+v", report.Timings)
+			}
+
+			if !tt.wantSuccess {
+				if !contains(report.Error, "failed to create release") || len(report.Assets) != 0 {
+					t.Errorf("expected failure without assets, got %+v", report)
+				}
+				return
+			}
+
+			if report.ReleaseURL == "" || report.ReleaseURL != resp.Outputs["release_url"] {
+				t.Errorf("expected release URL %v, got %q", resp.Outputs["release_url"], report.ReleaseURL)
+			}
+			if len(report.Assets) != 1 {
+				t.Fatalf("expected one asset, got %+v", report.Assets)
+			}
+			asset := report.Assets[0]
+			if asset.Name != "app.tar.gz" || asset.Size != 3 || !contains(asset.Digest, "sha256:") || !contains(asset.URL, "/packages/generic/release-assets/v1%2E4%2E0/app%2Etar%2Egz") {
+				t.Errorf("unexpected asset %+v", asset)
+			}
+			if len(report.Links) != 1 || report.Links[0].ID != 7 || report.Links[0].LinkType != "runbook" {
+				t.Errorf("unexpected links %+v", report.Links)
+			}
+			if len(report.Warnings) != 1 || !contains(report.Warnings[0], "asset missing.zip not published") {
+				t.Errorf("expected a warning for the missing asset, got %v", report.Warnings)
+			}
+		})
+	}
+}
+
+func TestExecuteReportFileDryRun(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+	tmpDir := t.TempDir()
+	chdirForTest(t, tmpDir)
+
+	p := &GitLabPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook:    plugin.HookPostPublish,
+		Config:  map[string]any{"token": "glpat-test", "project_id": "acme/app", "report_file": "release.json"},
+		Context: plugin.ReleaseContext{Version: "1.4.0", TagName: "v1.4.0"},
+		DryRun:  true,
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("unexpected result %+v, %v", resp, err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "release.json")); !os.IsNotExist(err) {
+		t.Errorf("expected no report on dry run, got %v", err)
+	}
+}
+
+func TestReportAddArtifacts(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, "https://gitlab.example.com")
+	cfg := &Config{BaseURL: "https://gitlab.example.com", PackageName: "acme"}
+
+	tests := []struct {
+		name     string
+		artifact plugin.Artifact
+		wantURL  string
+	}{
+		{
+			name:     "generic package file",
+			artifact: plugin.Artifact{Name: "app.tar.gz", Type: "generic_package", Path: "packages/generic/acme/v1.4.0/app.tar.gz"},
+			wantURL:  "https://gitlab.example.com/api/v4/projects/acme%2Fapp/packages/generic/acme/v1%2E4%2E0/app%2Etar%2Egz",
+		},
+		{
+			name:     "SBOM",
+			artifact: plugin.Artifact{Name: "sbom.cdx.json", Type: "sbom", Path: "packages/generic/acme/v1.4.0/sbom.cdx.json"},
+			wantURL:  "https://gitlab.example.com/api/v4/projects/acme%2Fapp/packages/generic/acme/v1%2E4%2E0/sbom%2Ecdx%2Ejson",
+		},
+		{
+			name:     "provenance",
+			artifact: plugin.Artifact{Name: "provenance.intoto.json", Type: "provenance", Path: "packages/generic/acme/v1.4.0/provenance.intoto.json"},
+			wantURL:  "https://gitlab.example.com/api/v4/projects/acme%2Fapp/packages/generic/acme/v1%2E4%2E0/provenance%2Eintoto%2Ejson",
+		},
+		{
+			name:     "container image",
+			artifact: plugin.Artifact{Name: "acme/app:1.4.0", Type: "container_image", Path: "registry.gitlab.example.com/acme/app:1.4.0"},
+			wantURL:  "https://registry.gitlab.example.com/acme/app:1.4.0",
+		},
+		{
+			name:     "package registry file",
+			artifact: plugin.Artifact{Name: "app-1.4.0.jar", Type: "maven_package", Path: "https://gitlab.example.com/api/v4/projects/acme%2Fapp/packages/maven/com/acme/app/1.4.0/app-1.4.0.jar"},
+			wantURL:  "https://gitlab.example.com/api/v4/projects/acme%2Fapp/packages/maven/com/acme/app/1.4.0/app-1.4.0.jar",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			report := &releaseReport{TagName: "v1.4.0"}
+			report.addArtifacts(client, cfg, "acme/app", []plugin.Artifact{tt.artifact})
+			if len(report.Assets) != 1 || report.Assets[0].URL != tt.wantURL {
+				t.Errorf("expected URL %q, got %+v", tt.wantURL, report.Assets)
+			}
+		})
+	}
+}
+
+func TestExecuteReportFileWriteFailure(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+	tmpDir := t.TempDir()
+	chdirForTest(t, tmpDir)
+	// A file where the report directory should be makes writing fail
+	if err := os.WriteFile(filepath.Join(tmpDir, "reports"), []byte("not a directory"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && contains(r.URL.Path, "/assets/links"):
+			_, _ = w.Write([]byte(`[]`))
+		case r.Method == http.MethodPost && contains(r.URL.Path, "/releases"):
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"tag_name": "v1.4.0", "name": "Release 1.4.0"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	p := &GitLabPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"token":       "glpat-test",
+			"base_url":    server.URL,
+			"project_id":  "acme/app",
+			"report_file": "reports/release.json",
+		},
+		Context: plugin.ReleaseContext{Version: "1.4.0", TagName: "v1.4.0"},
+	})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected the published release to succeed, got %+v", resp)
+	}
+	warnings, _ := resp.Outputs["warnings"].([]string)
+	if len(warnings) != 1 || !contains(warnings[0], "failed to write report reports/release.json") || !contains(resp.Message, "warning: failed to write report") {
+		t.Errorf("expected a report warning, got %q %v", resp.Message, warnings)
+	}
+}
@@ -0,0 +1,463 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// Supported SBOM formats.
+const (
+	sbomCycloneDX = "cyclonedx"
+	sbomSPDX      = "spdx"
+)
+
+// validSBOMFormats lists the accepted values of the sbom option.
+var validSBOMFormats = map[string]bool{
+	sbomCycloneDX: true,
+	sbomSPDX:      true,
+}
+
+// sbomToolName identifies the plugin as the SBOM author.
+const sbomToolName = "relicta-plugin-gitlab"
+
+// goModule is a module taken from go.mod, with its go.sum hash when known.
+type goModule struct {
+	Path     string
+	Version  string
+	Indirect bool
+	Sum      string
+}
+
+// purl returns the package URL of the module.
+func (m goModule) purl() string {
+	if m.Version == "" {
+		return "pkg:golang/" + m.Path
+	}
+	return fmt.Sprintf("pkg:golang/%s@%s", m.Path, m.Version)
+}
+
+// goModuleGraph is the main module and its requirements.
+type goModuleGraph struct {
+	Main     goModule
+	Requires []goModule
+}
+
+// parseGoModules reads go.mod and go.sum from dir. Replace directives are
+// applied so that the SBOM lists the modules that are actually built.
+func parseGoModules(dir string) (*goModuleGraph, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no supported manifest found for SBOM (expected go.mod)")
+		}
+		return nil, fmt.Errorf("failed to read go.mod: %w", err)
+	}
+
+	graph := &goModuleGraph{}
+	replacements := map[string]goModule{}
+	block := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line, comment, _ := strings.Cut(scanner.Text(), "//")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		for i, f := range fields {
+			fields[i] = strings.Trim(f, `"`)
+		}
+
+		if block != "" {
+			if fields[0] == ")" {
+				block = ""
+				continue
+			}
+			fields = append([]string{block}, fields...)
+		} else if len(fields) == 2 && fields[1] == "(" {
+			block = fields[0]
+			continue
+		}
+
+		switch fields[0] {
+		case "module":
+			if len(fields) >= 2 {
+				graph.Main.Path = fields[1]
+			}
+		case "require":
+			if len(fields) >= 3 {
+				graph.Requires = append(graph.Requires, goModule{
+					Path:     fields[1],
+					Version:  fields[2],
+					Indirect: strings.TrimSpace(comment) == "indirect",
+				})
+			}
+		case "replace":
+			// old [version] => new [version]
+			arrow := -1
+			for i, f := range fields {
+				if f == "=>" {
+					arrow = i
+				}
+			}
+			if arrow < 2 || arrow == len(fields)-1 {
+				continue
+			}
+			target := goModule{Path: fields[arrow+1]}
+			if arrow+2 < len(fields) {
+				target.Version = fields[arrow+2]
+			}
+			replacements[fields[1]] = target
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to parse go.mod: %w", err)
+	}
+	if graph.Main.Path == "" {
+		return nil, fmt.Errorf("go.mod has no module directive")
+	}
+
+	for i, req := range graph.Requires {
+		target, ok := replacements[req.Path]
+		if !ok {
+			continue
+		}
+		if target.Version == "" {
+			// Replaced by a local directory; no published version exists
+			graph.Requires[i].Version = ""
+			continue
+		}
+		graph.Requires[i].Path = target.Path
+		graph.Requires[i].Version = target.Version
+	}
+
+	sums, err := readGoSum(filepath.Join(dir, "go.sum"))
+	if err != nil {
+		return nil, err
+	}
+	for i, req := range graph.Requires {
+		graph.Requires[i].Sum = sums[req.Path+"@"+req.Version]
+	}
+
+	sort.Slice(graph.Requires, func(i, j int) bool {
+		return graph.Requires[i].Path < graph.Requires[j].Path
+	})
+	return graph, nil
+}
+
+// readGoSum returns the module content hashes from go.sum keyed by
+// path@version. A missing go.sum yields no hashes.
+func readGoSum(path string) (map[string]string, error) {
+	sums := map[string]string{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return sums, nil
+		}
+		return nil, fmt.Errorf("failed to read go.sum: %w", err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		sums[fields[0]+"@"+fields[1]] = fields[2]
+	}
+	return sums, nil
+}
+
+// cycloneDXDocument is a minimal CycloneDX 1.5 JSON BOM.
+type cycloneDXDocument struct {
+	BOMFormat    string                `json:"bomFormat"`
+	SpecVersion  string                `json:"specVersion"`
+	SerialNumber string                `json:"serialNumber"`
+	Version      int                   `json:"version"`
+	Metadata     cycloneDXMetadata     `json:"metadata"`
+	Components   []cycloneDXComponent  `json:"components"`
+	Dependencies []cycloneDXDependency `json:"dependencies"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     cycloneDXTools     `json:"tools"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXTools struct {
+	Components []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXComponent struct {
+	Type       string              `json:"type"`
+	BOMRef     string              `json:"bom-ref,omitempty"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version,omitempty"`
+	Scope      string              `json:"scope,omitempty"`
+	PURL       string              `json:"purl,omitempty"`
+	Properties []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// buildCycloneDX renders the module graph as a CycloneDX document.
+func buildCycloneDX(graph *goModuleGraph, created time.Time, serial string) *cycloneDXDocument {
+	root := cycloneDXComponent{
+		Type:    "application",
+		BOMRef:  graph.Main.purl(),
+		Name:    graph.Main.Path,
+		Version: graph.Main.Version,
+		PURL:    graph.Main.purl(),
+	}
+	doc := &cycloneDXDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + serial,
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: created.UTC().Format(time.RFC3339),
+			Tools:     cycloneDXTools{Components: []cycloneDXComponent{{Type: "application", Name: sbomToolName}}},
+			Component: root,
+		},
+		Components: []cycloneDXComponent{},
+	}
+
+	direct := []string{}
+	for _, mod := range graph.Requires {
+		component := cycloneDXComponent{
+			Type:    "library",
+			BOMRef:  mod.purl(),
+			Name:    mod.Path,
+			Version: mod.Version,
+			Scope:   "required",
+			PURL:    mod.purl(),
+		}
+		if mod.Sum != "" {
+			component.Properties = []cycloneDXProperty{{Name: "golang:sum", Value: mod.Sum}}
+		}
+		doc.Components = append(doc.Components, component)
+		if !mod.Indirect {
+			direct = append(direct, mod.purl())
+		}
+	}
+	doc.Dependencies = []cycloneDXDependency{{Ref: root.BOMRef, DependsOn: direct}}
+	return doc
+}
+
+// spdxDocument is a minimal SPDX 2.3 JSON document.
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// buildSPDX renders the module graph as an SPDX document.
+func buildSPDX(graph *goModuleGraph, created time.Time, serial string) *spdxDocument {
+	name := graph.Main.Path
+	if graph.Main.Version != "" {
+		name += "@" + graph.Main.Version
+	}
+	doc := &spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              name,
+		DocumentNamespace: fmt.Sprintf("https://spdx.org/spdxdocs/%s-%s", strings.ReplaceAll(name, "/", "-"), serial),
+		CreationInfo: spdxCreationInfo{
+			Created:  created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: " + sbomToolName},
+		},
+	}
+
+	modules := append([]goModule{graph.Main}, graph.Requires...)
+	for i, mod := range modules {
+		id := fmt.Sprintf("SPDXRef-Package-%d", i)
+		doc.Packages = append(doc.Packages, spdxPackage{
+			Name:             mod.Path,
+			SPDXID:           id,
+			VersionInfo:      mod.Version,
+			DownloadLocation: "NOASSERTION",
+			ExternalRefs: []spdxExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  mod.purl(),
+			}},
+		})
+		if i == 0 {
+			doc.Relationships = append(doc.Relationships, spdxRelationship{
+				SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: id,
+			})
+			continue
+		}
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID: "SPDXRef-Package-0", RelationshipType: "DEPENDS_ON", RelatedSPDXElement: id,
+		})
+	}
+	return doc
+}
+
+// goModuleDir returns the directory of the go.mod that builds dir: dir itself
+// or its nearest parent, as for go commands run in dir. The search stops at
+// the working directory.
+func goModuleDir(dir string) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	clean := filepath.Clean(dir)
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("sbom_dir must be within the working directory: %s", dir)
+	}
+	for {
+		goMod := filepath.Join(clean, "go.mod")
+		if _, err := os.Lstat(filepath.Join(cwd, goMod)); err == nil {
+			if _, err := validateAssetFile(goMod); err != nil {
+				return "", err
+			}
+			return filepath.Join(cwd, clean), nil
+		}
+		if clean == "." {
+			// parseGoModules reports the missing manifest
+			return cwd, nil
+		}
+		clean = filepath.Dir(clean)
+	}
+}
+
+// generateSBOM builds an SBOM for the Go module of dir and writes it to a
+// temporary file. The returned cleanup function removes the file.
+func generateSBOM(format, dir string, releaseCtx plugin.ReleaseContext) (string, func(), error) {
+	moduleDir, err := goModuleDir(dir)
+	if err != nil {
+		return "", func() {}, err
+	}
+
+	graph, err := parseGoModules(moduleDir)
+	if err != nil {
+		return "", func() {}, err
+	}
+	if releaseCtx.Version != "" {
+		graph.Main.Version = "v" + strings.TrimPrefix(releaseCtx.Version, "v")
+	}
+
+	serial, err := newUUID()
+	if err != nil {
+		return "", func() {}, err
+	}
+
+	var doc any
+	fileName := "sbom.cdx.json"
+	if format == sbomSPDX {
+		doc = buildSPDX(graph, time.Now(), serial)
+		fileName = "sbom.spdx.json"
+	} else {
+		doc = buildCycloneDX(graph, time.Now(), serial)
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", func() {}, fmt.Errorf("failed to encode SBOM: %w", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "relicta-gitlab-sbom-")
+	if err != nil {
+		return "", func() {}, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	cleanup := func() { _ = os.RemoveAll(tmpDir) }
+
+	path := filepath.Join(tmpDir, fileName)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		cleanup()
+		return "", func() {}, fmt.Errorf("failed to write SBOM: %w", err)
+	}
+	return path, cleanup, nil
+}
+
+// attachSBOM uploads a generated SBOM to the generic package and links it
+// from the release.
+func (p *GitLabPlugin) attachSBOM(ctx context.Context, client *gitlab.Client, projectID, packageName, tagName, format, sbomPath string) (*plugin.Artifact, error) {
+	artifact, err := p.publishGenericFile(ctx, client, projectID, packageName, tagName, sbomPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload SBOM: %w", err)
+	}
+	artifact.Type = "sbom"
+
+	label := "CycloneDX"
+	if format == sbomSPDX {
+		label = "SPDX"
+	}
+	fileName := filepath.Base(sbomPath)
+	link := AssetLink{
+		Name:     fmt.Sprintf("SBOM (%s)", label),
+		URL:      genericPackageURL(client, projectID, packageName, tagName, fileName),
+		FilePath: "/" + fileName,
+		LinkType: "other",
+	}
+	if err := p.createAssetLink(ctx, client, projectID, tagName, link); err != nil {
+		return nil, fmt.Errorf("failed to link SBOM: %w", err)
+	}
+	return artifact, nil
+}
+
+// newUUID returns a random RFC 4122 version 4 UUID.
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate UUID: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
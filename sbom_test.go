@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+const testGoMod = `module github.com/acme/tool
+
+go 1.24
+
+require (
+	github.com/acme/lib v1.2.0
+	github.com/old/fork v0.1.0
+	golang.org/x/text v0.14.0 // indirect
+)
+
+require github.com/local/thing v0.0.0
+
+replace github.com/old/fork => github.com/new/fork v0.2.0
+
+replace (
+	github.com/local/thing => ../thing
+)
+`
+
+const testGoSum = `github.com/acme/lib v1.2.0 h1:libhash=
+github.com/acme/lib v1.2.0/go.mod h1:libmodhash=
+github.com/new/fork v0.2.0 h1:forkhash=
+golang.org/x/text v0.14.0/go.mod h1:textmodhash=
+`
+
+func writeGoManifests(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(testGoMod), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.sum"), []byte(testGoSum), 0644); err != nil {
+		t.Fatalf("failed to write go.sum: %v", err)
+	}
+}
+
+func TestParseGoModules(t *testing.T) {
+	dir := t.TempDir()
+	writeGoManifests(t, dir)
+
+	graph, err := parseGoModules(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if graph.Main.Path != "github.com/acme/tool" {
+		t.Errorf("unexpected main module %q", graph.Main.Path)
+	}
+
+	want := []goModule{
+		{Path: "github.com/acme/lib", Version: "v1.2.0", Sum: "h1:libhash="},
+		{Path: "github.com/local/thing"},
+		{Path: "github.com/new/fork", Version: "v0.2.0", Sum: "h1:forkhash="},
+		{Path: "golang.org/x/text", Version: "v0.14.0", Indirect: true},
+	}
+	if len(graph.Requires) != len(want) {
+		t.Fatalf("expected %d modules, got %+v", len(want), graph.Requires)
+	}
+	for i := range want {
+		if graph.Requires[i] != want[i] {
+			t.Errorf("module %d: got %+v, want %+v", i, graph.Requires[i], want[i])
+		}
+	}
+
+	if _, err := parseGoModules(t.TempDir()); err == nil || !contains(err.Error(), "no supported manifest") {
+		t.Errorf("expected missing manifest error, got %v", err)
+	}
+}
+
+func TestBuildSBOMDocuments(t *testing.T) {
+	graph := &goModuleGraph{
+		Main: goModule{Path: "github.com/acme/tool", Version: "v1.0.0"},
+		Requires: []goModule{
+			{Path: "github.com/acme/lib", Version: "v1.2.0", Sum: "h1:libhash="},
+			{Path: "golang.org/x/text", Version: "v0.14.0", Indirect: true},
+		},
+	}
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("cyclonedx", func(t *testing.T) {
+		doc := buildCycloneDX(graph, created, "0000-1111")
+		if doc.BOMFormat != "CycloneDX" || doc.SerialNumber != "urn:uuid:0000-1111" {
+			t.Errorf("unexpected header %+v", doc)
+		}
+		if doc.Metadata.Component.PURL != "pkg:golang/github.com/acme/tool@v1.0.0" {
+			t.Errorf("unexpected root purl %q", doc.Metadata.Component.PURL)
+		}
+		if len(doc.Components) != 2 || doc.Components[0].Properties[0].Value != "h1:libhash=" {
+			t.Errorf("unexpected components %+v", doc.Components)
+		}
+		deps := doc.Dependencies[0].DependsOn
+		if len(deps) != 1 || deps[0] != "pkg:golang/github.com/acme/lib@v1.2.0" {
+			t.Errorf("expected only direct dependencies, got %v", deps)
+		}
+	})
+
+	t.Run("spdx", func(t *testing.T) {
+		doc := buildSPDX(graph, created, "0000-1111")
+		if doc.SPDXVersion != "SPDX-2.3" || doc.Name != "github.com/acme/tool@v1.0.0" {
+			t.Errorf("unexpected header %+v", doc)
+		}
+		if len(doc.Packages) != 3 || doc.Packages[2].ExternalRefs[0].ReferenceLocator != "pkg:golang/golang.org/x/text@v0.14.0" {
+			t.Errorf("unexpected packages %+v", doc.Packages)
+		}
+		if len(doc.Relationships) != 3 || doc.Relationships[0].RelationshipType != "DESCRIBES" || doc.Relationships[1].RelationshipType != "DEPENDS_ON" {
+			t.Errorf("unexpected relationships %+v", doc.Relationships)
+		}
+	})
+}
+
+func TestGoModuleDir(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+
+	tmpDir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	chdirForTest(t, tmpDir)
+	writeGoManifests(t, tmpDir)
+	if err := os.MkdirAll(filepath.Join(tmpDir, "sdk", "internal"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "sdk", "go.mod"), []byte("module github.com/acme/sdk\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		dir     string
+		want    string
+		wantErr bool
+	}{
+		{dir: "", want: tmpDir},
+		{dir: ".", want: tmpDir},
+		{dir: "sdk", want: filepath.Join(tmpDir, "sdk")},
+		{dir: "sdk/internal", want: filepath.Join(tmpDir, "sdk")},
+		{dir: "services/api", want: tmpDir},
+		{dir: "../other", wantErr: true},
+		{dir: "/etc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			got, err := goModuleDir(tt.dir)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCreateReleaseAttachesSBOM(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+
+	tmpDir := t.TempDir()
+	chdirForTest(t, tmpDir)
+	writeGoManifests(t, tmpDir)
+
+	var mu sync.Mutex
+	var sbom cycloneDXDocument
+	var linkRequests []map[string]any
+	releaseCreated := false
+
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case contains(r.URL.Path, "/packages/generic/release-assets/v1.0.0/sbom.cdx.json"):
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &sbom)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		case contains(r.URL.Path, "/assets/links") && r.Method == http.MethodPost:
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			linkRequests = append(linkRequests, body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 1}`))
+		case contains(r.URL.Path, "/releases") && r.Method == http.MethodPost:
+			releaseCreated = true
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(gitlab.Release{TagName: "v1.0.0"})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	p := &GitLabPlugin{}
+	cfg := &Config{Token: "glpat-test", ProjectID: "group/project", BaseURL: server.URL, SBOM: "cyclonedx"}
+
+	resp, err := p.createRelease(context.Background(), cfg, plugin.ReleaseContext{Version: "1.0.0", TagName: "v1.0.0"}, false)
+	if err != nil {
+		t.Fatalf("createRelease returned error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	if len(resp.Artifacts) != 1 || resp.Artifacts[0].Type != "sbom" {
+		t.Errorf("expected sbom artifact, got %+v", resp.Artifacts)
+	}
+
+	mu.Lock()
+	if sbom.Metadata.Component.Version != "v1.0.0" || len(sbom.Components) != 4 {
+		t.Errorf("unexpected uploaded SBOM %+v", sbom)
+	}
+	if len(linkRequests) != 1 || linkRequests[0]["name"] != "SBOM (CycloneDX)" || linkRequests[0]["link_type"] != "other" {
+		t.Errorf("unexpected SBOM link %v", linkRequests)
+	}
+	releaseCreated = false
+	mu.Unlock()
+
+	// Without a manifest the release must not be created
+	chdirForTest(t, t.TempDir())
+	resp, err = p.createRelease(context.Background(), cfg, plugin.ReleaseContext{Version: "1.0.0", TagName: "v1.0.0"}, false)
+	if err != nil {
+		t.Fatalf("createRelease returned error: %v", err)
+	}
+	if resp.Success || !contains(resp.Error, "failed to generate SBOM") {
+		t.Errorf("expected SBOM failure, got %+v", resp)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if releaseCreated {
+		t.Error("release should not be created when SBOM generation fails")
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// semverPattern matches a semantic version with an optional leading "v".
+var semverPattern = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z.-]+))?(?:\+([0-9A-Za-z.-]+))?$`)
+
+// semanticVersion is a parsed semantic version (https://semver.org).
+type semanticVersion struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+	Build      string
+}
+
+// parseSemver parses a semantic version such as "1.2.3", "v2.0.0-rc.1" or "1.0.0+build.5".
+func parseSemver(version string) (*semanticVersion, error) {
+	m := semverPattern.FindStringSubmatch(strings.TrimSpace(version))
+	if m == nil {
+		return nil, fmt.Errorf("invalid semantic version: %q", version)
+	}
+
+	v := &semanticVersion{Prerelease: m[4], Build: m[5]}
+	// The pattern guarantees numeric components
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	v.Patch, _ = strconv.Atoi(m[3])
+	return v, nil
+}
+
+// IsPrerelease reports whether the version has a prerelease suffix.
+func (v *semanticVersion) IsPrerelease() bool {
+	return v.Prerelease != ""
+}
+
+// Compare returns -1, 0 or 1 depending on whether v has lower, equal or
+// higher precedence than o. Build metadata is ignored.
+func (v *semanticVersion) Compare(o *semanticVersion) int {
+	for _, d := range []int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+
+	// A version without a prerelease has higher precedence
+	switch {
+	case v.Prerelease == o.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case o.Prerelease == "":
+		return -1
+	}
+
+	a, b := strings.Split(v.Prerelease, "."), strings.Split(o.Prerelease, ".")
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := comparePrereleaseIdentifier(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return sign(len(a) - len(b))
+}
+
+// comparePrereleaseIdentifier compares dot-separated prerelease identifiers:
+// numeric ones numerically and below alphanumeric ones, which compare in
+// ASCII order.
+func comparePrereleaseIdentifier(a, b string) int {
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return sign(an - bn)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// sign returns -1, 0 or 1 for the sign of n.
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
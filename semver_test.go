@@ -0,0 +1,76 @@
+package main
+
+import "testing"
+
+func TestParseSemver(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input          string
+		wantMajor      int
+		wantMinor      int
+		wantPatch      int
+		wantPrerelease string
+		wantBuild      string
+		wantError      bool
+	}{
+		{input: "1.2.3", wantMajor: 1, wantMinor: 2, wantPatch: 3},
+		{input: "v10.0.1", wantMajor: 10, wantPatch: 1},
+		{input: "1.0.0-rc.1", wantMajor: 1, wantPrerelease: "rc.1"},
+		{input: "2.1.0-beta+exp.sha.5114f85", wantMajor: 2, wantMinor: 1, wantPrerelease: "beta", wantBuild: "exp.sha.5114f85"},
+		{input: "1.2", wantError: true},
+		{input: "01.2.3", wantError: true},
+		{input: "1.2.3-", wantError: true},
+		{input: "", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			v, err := parseSemver(tt.input)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("expected error, got %+v", v)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if v.Major != tt.wantMajor || v.Minor != tt.wantMinor || v.Patch != tt.wantPatch {
+				t.Errorf("expected %d.%d.%d, got %d.%d.%d", tt.wantMajor, tt.wantMinor, tt.wantPatch, v.Major, v.Minor, v.Patch)
+			}
+			if v.Prerelease != tt.wantPrerelease || v.Build != tt.wantBuild {
+				t.Errorf("expected prerelease %q build %q, got %q %q", tt.wantPrerelease, tt.wantBuild, v.Prerelease, v.Build)
+			}
+			if v.IsPrerelease() != (tt.wantPrerelease != "") {
+				t.Errorf("IsPrerelease mismatch for %q", tt.input)
+			}
+		})
+	}
+}
+
+func TestSemverCompare(t *testing.T) {
+	t.Parallel()
+
+	// Each version has lower precedence than the next (semver.org §11)
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+		"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.8.5", "1.10.0", "2.0.0",
+	}
+	for i := 0; i < len(ordered)-1; i++ {
+		lower, _ := parseSemver(ordered[i])
+		higher, _ := parseSemver(ordered[i+1])
+		if got := lower.Compare(higher); got != -1 {
+			t.Errorf("%s.Compare(%s) = %d, want -1", ordered[i], ordered[i+1], got)
+		}
+		if got := higher.Compare(lower); got != 1 {
+			t.Errorf("%s.Compare(%s) = %d, want 1", ordered[i+1], ordered[i], got)
+		}
+	}
+
+	a, _ := parseSemver("v1.2.3+build.1")
+	b, _ := parseSemver("1.2.3+build.2")
+	if got := a.Compare(b); got != 0 {
+		t.Errorf("expected build metadata to be ignored, got %d", got)
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// Target is an additional GitLab project, possibly on another instance, that
+// the release is published to.
+type Target struct {
+	// Name identifies the target in messages and outputs.
+	Name string `json:"name,omitempty"`
+	// BaseURL is the GitLab instance URL (default: the top-level base_url).
+	BaseURL string `json:"base_url,omitempty"`
+	// ProjectID is the project ID or path on that instance.
+	ProjectID string `json:"project_id,omitempty"`
+	// TokenEnv names the environment variable holding the token for this target.
+	TokenEnv string `json:"token_env,omitempty"`
+	// Overrides replaces top-level options (e.g. name, assets) for this target.
+	Overrides map[string]any `json:"overrides,omitempty"`
+}
+
+// Partial failure policies for multi-target publishing.
+const (
+	// partialFailureFail publishes to every target and fails if any failed.
+	partialFailureFail = "fail"
+	// partialFailureFailFast stops at the first failed target.
+	partialFailureFailFast = "fail_fast"
+	// partialFailureAllow succeeds when at least one target succeeded.
+	partialFailureAllow = "allow"
+)
+
+// validPartialFailurePolicies lists the supported partial_failure values.
+var validPartialFailurePolicies = map[string]bool{
+	partialFailureFail:     true,
+	partialFailureFailFast: true,
+	partialFailureAllow:    true,
+}
+
+// targetResult is the outcome of publishing to one target, reported in Outputs.
+type targetResult struct {
+	Target    string         `json:"target"`
+	BaseURL   string         `json:"base_url"`
+	ProjectID string         `json:"project_id"`
+	Success   bool           `json:"success"`
+	Message   string         `json:"message,omitempty"`
+	Error     string         `json:"error,omitempty"`
+	Outputs   map[string]any `json:"outputs,omitempty"`
+}
+
+// normalizeBaseURL returns the instance URL without trailing slashes or the
+// API suffix, defaulting to gitlab.com.
+func normalizeBaseURL(baseURL string) string {
+	if baseURL == "" {
+		return "https://gitlab.com"
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+	baseURL = strings.TrimSuffix(baseURL, "/api/v4")
+	return strings.TrimRight(baseURL, "/")
+}
+
+// targetName returns the configured target name or a name derived from its
+// instance and project.
+func targetName(target Target, cfg *Config) string {
+	if target.Name != "" {
+		return target.Name
+	}
+	host := strings.TrimPrefix(strings.TrimPrefix(normalizeBaseURL(cfg.BaseURL), "https://"), "http://")
+	if cfg.ProjectID == "" {
+		return host
+	}
+	return host + "/" + cfg.ProjectID
+}
+
+// targetConfig builds the configuration for one target: the top-level options,
+// then the target's overrides, then its instance and project. The top-level
+// token is only reused for targets whose resulting base_url is the same
+// instance.
+func (p *GitLabPlugin) targetConfig(raw map[string]any, target Target) (*Config, error) {
+	merged := make(map[string]any, len(raw)+len(target.Overrides)+2)
+	for key, value := range raw {
+		if key == "targets" || key == "partial_failure" {
+			continue
+		}
+		merged[key] = value
+	}
+	for key, value := range target.Overrides {
+		merged[key] = value
+	}
+	if target.BaseURL != "" {
+		merged["base_url"] = target.BaseURL
+	}
+	if target.ProjectID != "" {
+		merged["project_id"] = target.ProjectID
+	}
+
+	// The instance may come from the target or from overrides.base_url
+	sameInstance := normalizeBaseURL(stringValue(merged["base_url"])) == normalizeBaseURL(stringValue(raw["base_url"]))
+	if _, ok := target.Overrides["token"]; !ok && !sameInstance {
+		// Never send the primary instance's token to another instance
+		delete(merged, "token")
+	}
+
+	cfg := p.parseConfig(merged)
+	if target.TokenEnv != "" {
+		cfg.Token = os.Getenv(target.TokenEnv)
+		if cfg.Token == "" {
+			return cfg, fmt.Errorf("token variable %s is not set", target.TokenEnv)
+		}
+	} else if cfg.Token == "" && !sameInstance {
+		return cfg, fmt.Errorf("token_env is required for targets on another GitLab instance")
+	}
+	return cfg, nil
+}
+
+// stringValue returns v if it is a string.
+func stringValue(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+// releaseProject is a project that a release is published to, with the
+// configuration and release context it is published with.
+type releaseProject struct {
+	cfg        *Config
+	releaseCtx plugin.ReleaseContext
+	// err reports a target that cannot be published to, e.g. without a token.
+	err error
+}
+
+// releaseProjects returns every project that post_publish publishes the
+// release to: each target of each component, or the top-level project.
+func (p *GitLabPlugin) releaseProjects(raw map[string]any, cfg *Config, releaseCtx plugin.ReleaseContext) []releaseProject {
+	if len(cfg.Components) > 0 {
+		var projects []releaseProject
+		for _, component := range cfg.Components {
+			componentRaw, componentCtx := componentRelease(raw, component, releaseCtx)
+			projects = append(projects, p.releaseProjects(componentRaw, p.parseConfig(componentRaw), componentCtx)...)
+		}
+		return projects
+	}
+	if len(cfg.Targets) == 0 {
+		return []releaseProject{{cfg: cfg, releaseCtx: releaseCtx}}
+	}
+
+	projects := make([]releaseProject, 0, len(cfg.Targets))
+	for _, target := range cfg.Targets {
+		targetCfg, err := p.targetConfig(raw, target)
+		if err != nil {
+			err = fmt.Errorf("target %s: %w", targetName(target, targetCfg), err)
+		}
+		projects = append(projects, releaseProject{cfg: targetCfg, releaseCtx: releaseCtx, err: err})
+	}
+	return projects
+}
+
+// publishTargets creates the release in every configured target and
+// aggregates the results according to the partial_failure policy.
+func (p *GitLabPlugin) publishTargets(ctx context.Context, raw map[string]any, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool) (*plugin.ExecuteResponse, error) {
+	policy := cfg.PartialFailure
+	if policy == "" {
+		policy = partialFailureFail
+	}
+
+	var results []targetResult
+	var artifacts []plugin.Artifact
+	var failed []string
+	for _, target := range cfg.Targets {
+		targetCfg, err := p.targetConfig(raw, target)
+		result := targetResult{
+			Target:    targetName(target, targetCfg),
+			BaseURL:   normalizeBaseURL(targetCfg.BaseURL),
+			ProjectID: resolveProjectID(targetCfg, releaseCtx),
+		}
+
+		var resp *plugin.ExecuteResponse
+		if err == nil {
+			resp, err = p.createRelease(ctx, targetCfg, releaseCtx, dryRun)
+		}
+		switch {
+		case err != nil:
+			result.Error = err.Error()
+		case resp.Success:
+			result.Success = true
+			result.Message = resp.Message
+			result.Outputs = resp.Outputs
+			artifacts = append(artifacts, resp.Artifacts...)
+		default:
+			result.Error = resp.Error
+			result.Outputs = resp.Outputs
+		}
+		results = append(results, result)
+
+		if !result.Success {
+			failed = append(failed, fmt.Sprintf("%s: %s", result.Target, result.Error))
+			if policy == partialFailureFailFast {
+				break
+			}
+		}
+	}
+
+	succeeded := len(results) - len(failed)
+	resp := &plugin.ExecuteResponse{
+		Success:   len(failed) == 0 || (policy == partialFailureAllow && succeeded > 0),
+		Message:   fmt.Sprintf("Published to %d of %d targets", succeeded, len(cfg.Targets)),
+		Artifacts: artifacts,
+		Outputs: map[string]any{
+			"targets": results,
+		},
+	}
+	if dryRun {
+		resp.Message = fmt.Sprintf("Would publish to %d targets", len(cfg.Targets))
+	}
+	if len(failed) > 0 {
+		resp.Error = fmt.Sprintf("%d of %d targets failed: %s", len(failed), len(cfg.Targets), strings.Join(failed, "; "))
+	}
+	return resp, nil
+}
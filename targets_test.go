@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// releaseServer accepts release creation and records the token and release
+// name of each request. With fail set, it rejects every request.
+func releaseServer(t *testing.T, fail bool) (string, *[]map[string]string) {
+	t.Helper()
+	var requests []map[string]string
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if !contains(r.URL.Path, "/releases") || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		name, _ := body["name"].(string)
+		requests = append(requests, map[string]string{
+			"path":  r.URL.EscapedPath(),
+			"token": r.Header.Get("PRIVATE-TOKEN"),
+			"name":  name,
+		})
+		if fail {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message": "403 Forbidden"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(gitlab.Release{TagName: "v1.0.0", Name: name})
+	})
+	return server.URL, &requests
+}
+
+func TestTargetConfig(t *testing.T) {
+	raw := map[string]any{
+		"base_url":   "https://gitlab.internal.example.com",
+		"project_id": "platform/app",
+		"token":      "internal-token",
+		"name":       "Release {version}",
+		"targets":    []any{},
+	}
+	t.Setenv("PUBLIC_GITLAB_TOKEN", "public-token")
+
+	tests := []struct {
+		name          string
+		target        Target
+		wantBaseURL   string
+		wantProjectID string
+		wantToken     string
+		wantName      string
+		wantErr       string
+	}{
+		{
+			name:          "same instance inherits the token",
+			target:        Target{ProjectID: "platform/app-mirror"},
+			wantBaseURL:   "https://gitlab.internal.example.com",
+			wantProjectID: "platform/app-mirror",
+			wantToken:     "internal-token",
+			wantName:      "Release {version}",
+		},
+		{
+			name:          "other instance uses token_env and overrides",
+			target:        Target{BaseURL: "https://gitlab.com", ProjectID: "acme/app", TokenEnv: "PUBLIC_GITLAB_TOKEN", Overrides: map[string]any{"name": "App {version}"}},
+			wantBaseURL:   "https://gitlab.com",
+			wantProjectID: "acme/app",
+			wantToken:     "public-token",
+			wantName:      "App {version}",
+		},
+		{
+			name:    "other instance without a token",
+			target:  Target{BaseURL: "https://gitlab.com", ProjectID: "acme/app"},
+			wantErr: "token_env is required",
+		},
+		{
+			name:    "other instance from overrides without a token",
+			target:  Target{ProjectID: "acme/app", Overrides: map[string]any{"base_url": "https://gitlab.com"}},
+			wantErr: "token_env is required",
+		},
+		{
+			name:    "unset token variable",
+			target:  Target{BaseURL: "https://gitlab.com", TokenEnv: "MISSING_GITLAB_TOKEN"},
+			wantErr: "MISSING_GITLAB_TOKEN is not set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &GitLabPlugin{}
+			cfg, err := p.targetConfig(raw, tt.target)
+			if tt.wantErr != "" {
+				if err == nil || !contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				if cfg.Token == "internal-token" {
+					t.Errorf("primary token leaked to another instance")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("targetConfig returned error: %v", err)
+			}
+			if cfg.BaseURL != tt.wantBaseURL || cfg.ProjectID != tt.wantProjectID || cfg.Token != tt.wantToken || cfg.Name != tt.wantName {
+				t.Errorf("unexpected config base_url=%q project_id=%q token=%q name=%q", cfg.BaseURL, cfg.ProjectID, cfg.Token, cfg.Name)
+			}
+			if len(cfg.Targets) != 0 {
+				t.Errorf("target config must not contain targets")
+			}
+		})
+	}
+}
+
+func TestExecutePublishTargets(t *testing.T) {
+	tests := []struct {
+		name           string
+		policy         string
+		internalFails  bool
+		wantSuccess    bool
+		wantMessage    string
+		wantPublicHits int
+	}{
+		{
+			name:           "all targets succeed",
+			wantSuccess:    true,
+			wantMessage:    "Published to 2 of 2 targets",
+			wantPublicHits: 1,
+		},
+		{
+			name:           "failure fails the run but publishes everywhere",
+			internalFails:  true,
+			wantSuccess:    false,
+			wantMessage:    "Published to 1 of 2 targets",
+			wantPublicHits: 1,
+		},
+		{
+			name:           "fail_fast stops at the first failure",
+			policy:         "fail_fast",
+			internalFails:  true,
+			wantSuccess:    false,
+			wantMessage:    "Published to 0 of 2 targets",
+			wantPublicHits: 0,
+		},
+		{
+			name:           "allow succeeds with one target",
+			policy:         "allow",
+			internalFails:  true,
+			wantSuccess:    true,
+			wantMessage:    "Published to 1 of 2 targets",
+			wantPublicHits: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			internalURL, internalReqs := releaseServer(t, tt.internalFails)
+			publicURL, publicReqs := releaseServer(t, false)
+			t.Setenv("PUBLIC_GITLAB_TOKEN", "public-token")
+
+			config := map[string]any{
+				"base_url":   internalURL,
+				"project_id": "platform/app",
+				"token":      "internal-token",
+				"targets": []any{
+					map[string]any{"name": "internal"},
+					map[string]any{
+						"name":       "public",
+						"base_url":   publicURL,
+						"project_id": "acme/app",
+						"token_env":  "PUBLIC_GITLAB_TOKEN",
+						"overrides":  map[string]any{"name": "Acme App"},
+					},
+				},
+			}
+			if tt.policy != "" {
+				config["partial_failure"] = tt.policy
+			}
+
+			p := &GitLabPlugin{}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook:    plugin.HookPostPublish,
+				Config:  config,
+				Context: plugin.ReleaseContext{Version: "1.0.0", TagName: "v1.0.0"},
+			})
+			if err != nil {
+				t.Fatalf("Execute returned error: %v", err)
+			}
+			if resp.Success != tt.wantSuccess || resp.Message != tt.wantMessage {
+				t.Fatalf("unexpected response %+v", resp)
+			}
+			if tt.internalFails && !contains(resp.Error, "internal:") {
+				t.Errorf("expected error to name the failed target, got %q", resp.Error)
+			}
+
+			if len(*internalReqs) != 1 || (*internalReqs)[0]["token"] != "internal-token" || (*internalReqs)[0]["path"] != "/api/v4/projects/platform%2Fapp/releases" {
+				t.Errorf("unexpected internal requests %v", *internalReqs)
+			}
+			if len(*publicReqs) != tt.wantPublicHits {
+				t.Fatalf("expected %d public requests, got %v", tt.wantPublicHits, *publicReqs)
+			}
+			if tt.wantPublicHits > 0 {
+				req := (*publicReqs)[0]
+				if req["token"] != "public-token" || req["name"] != "Acme App" || req["path"] != "/api/v4/projects/acme%2Fapp/releases" {
+					t.Errorf("unexpected public request %v", req)
+				}
+			}
+
+			results := resp.Outputs["targets"].([]targetResult)
+			if results[0].Target != "internal" || results[0].Success == tt.internalFails {
+				t.Errorf("unexpected internal result %+v", results[0])
+			}
+			if tt.wantPublicHits > 0 && (!results[1].Success || results[1].Outputs["release_url"] == nil) {
+				t.Errorf("unexpected public result %+v", results[1])
+			}
+		})
+	}
+}
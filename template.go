@@ -0,0 +1,21 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// renderTemplate replaces release placeholders such as {version} and
+// {tag_name} in tmpl. Unknown placeholders are left untouched.
+func renderTemplate(tmpl string, releaseCtx plugin.ReleaseContext) string {
+	return strings.NewReplacer(
+		"{version}", releaseCtx.Version,
+		"{previous_version}", releaseCtx.PreviousVersion,
+		"{tag_name}", releaseCtx.TagName,
+		"{release_type}", releaseCtx.ReleaseType,
+		"{branch}", releaseCtx.Branch,
+		"{commit_sha}", releaseCtx.CommitSHA,
+		"{repository_url}", releaseCtx.RepositoryURL,
+	).Replace(tmpl)
+}
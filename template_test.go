@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{
+		Version:         "1.4.0",
+		PreviousVersion: "1.3.2",
+		TagName:         "v1.4.0",
+		ReleaseType:     "minor",
+		Branch:          "main",
+		CommitSHA:       "abc123",
+		RepositoryURL:   "https://gitlab.com/group/project",
+	}
+
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{tmpl: "Release {version}", want: "Release 1.4.0"},
+		{tmpl: "{previous_version}..{tag_name}", want: "1.3.2..v1.4.0"},
+		{tmpl: "{release_type} from {branch}@{commit_sha}", want: "minor from main@abc123"},
+		{tmpl: "{repository_url}/-/tags/{tag_name}", want: "https://gitlab.com/group/project/-/tags/v1.4.0"},
+		{tmpl: "{unknown} stays", want: "{unknown} stays"},
+		{tmpl: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tmpl, func(t *testing.T) {
+			if got := renderTemplate(tt.tmpl, releaseCtx); got != tt.want {
+				t.Errorf("renderTemplate(%q) = %q, want %q", tt.tmpl, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// registryTerraform is the asset registry value for Terraform modules.
+const registryTerraform = "terraform"
+
+var (
+	// terraformModuleDirPattern matches the conventional terraform-<system>-<name> directory name.
+	terraformModuleDirPattern = regexp.MustCompile(`^terraform-([a-z0-9]+)-([a-z0-9-]+)$`)
+	// terraformNamePattern and terraformSystemPattern mirror GitLab's module name rules.
+	terraformNamePattern   = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+	terraformSystemPattern = regexp.MustCompile(`^[a-z0-9]+$`)
+)
+
+// terraformModuleID returns the module name and system for an asset. Explicit
+// options win; otherwise they are taken from a terraform-<system>-<name>
+// directory or file name.
+func terraformModuleID(asset Asset, modulePath string) (string, string, error) {
+	name, system := asset.ModuleName, asset.ModuleSystem
+	if name == "" || system == "" {
+		base := filepath.Base(modulePath)
+		base = strings.TrimSuffix(strings.TrimSuffix(base, ".tgz"), ".tar.gz")
+		if m := terraformModuleDirPattern.FindStringSubmatch(base); m != nil {
+			if system == "" {
+				system = m[1]
+			}
+			if name == "" {
+				name = m[2]
+			}
+		}
+	}
+
+	if name == "" || system == "" {
+		return "", "", fmt.Errorf("terraform module requires module_name and module_system (or a terraform-<system>-<name> path): %s", asset.Path)
+	}
+	if !terraformNamePattern.MatchString(name) {
+		return "", "", fmt.Errorf("invalid terraform module name %q (use lowercase letters, digits and hyphens)", name)
+	}
+	if !terraformSystemPattern.MatchString(system) {
+		return "", "", fmt.Errorf("invalid terraform module system %q (use lowercase letters and digits)", system)
+	}
+	return name, system, nil
+}
+
+// publishTerraformModule packages a module directory (or uses an existing
+// .tgz) and uploads it to the project's Terraform module registry.
+func (p *GitLabPlugin) publishTerraformModule(ctx context.Context, client *gitlab.Client, projectID string, releaseCtx plugin.ReleaseContext, asset Asset) (*publishedAsset, error) {
+	modulePath, err := validateAssetPath(asset.Path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asset path %s: %w", asset.Path, err)
+	}
+
+	info, err := os.Lstat(modulePath)
+	if err != nil {
+		return nil, fmt.Errorf("asset file not accessible %s: %w", asset.Path, err)
+	}
+
+	name, system, err := terraformModuleID(asset, modulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := parseSemver(releaseCtx.Version); err != nil {
+		return nil, fmt.Errorf("terraform module version must be semantic: %w", err)
+	}
+	moduleVersion := strings.TrimPrefix(releaseCtx.Version, "v")
+
+	packagePath := modulePath
+	if info.IsDir() {
+		path, cleanup, err := packageTerraformModule(modulePath, name, system, moduleVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to package terraform module %s: %w", asset.Path, err)
+		}
+		defer cleanup()
+		packagePath = path
+	} else if packagePath, err = validateAssetFile(asset.Path); err != nil {
+		return nil, err
+	}
+
+	apiPath := fmt.Sprintf("projects/%s/packages/terraform/modules/%s/%s/%s/file",
+		gitlab.PathEscape(projectID), gitlab.PathEscape(name), gitlab.PathEscape(system), gitlab.PathEscape(moduleVersion))
+
+	artifact, err := putPackageFile(ctx, client, apiPath, nil, packagePath, filepath.Base(packagePath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload terraform module %s/%s: %w", name, system, err)
+	}
+	artifact.Type = "terraform_module"
+
+	// Modules are consumed through the namespace-scoped registry API, where
+	// the namespace is the project's top-level group.
+	path, err := projectPath(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+	namespace, _, _ := strings.Cut(path, "/")
+	downloadURL := fmt.Sprintf("%spackages/terraform/modules/v1/%s/%s/%s/%s/file",
+		client.BaseURL().String(), gitlab.PathEscape(namespace), gitlab.PathEscape(name), gitlab.PathEscape(system), gitlab.PathEscape(moduleVersion))
+	artifact.Path = downloadURL
+
+	return &publishedAsset{
+		artifacts: []plugin.Artifact{*artifact},
+		link: &AssetLink{
+			Name:     fmt.Sprintf("%s/%s %s (Terraform)", name, system, moduleVersion),
+			URL:      downloadURL,
+			LinkType: "package",
+		},
+	}, nil
+}
+
+// packageTerraformModule writes the module directory to a temporary tarball
+// with the module files at the archive root, as Terraform expects.
+func packageTerraformModule(dir, name, system, version string) (string, func(), error) {
+	tmpDir, err := os.MkdirTemp("", "relicta-gitlab-terraform-")
+	if err != nil {
+		return "", func() {}, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	cleanup := func() { _ = os.RemoveAll(tmpDir) }
+
+	archivePath := filepath.Join(tmpDir, fmt.Sprintf("%s-%s-%s.tgz", name, system, version))
+	out, err := os.Create(archivePath)
+	if err != nil {
+		cleanup()
+		return "", func() {}, fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	err = writeTarGzArchive(out, dir, "")
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		return "", func() {}, err
+	}
+
+	return archivePath, cleanup, nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestTerraformModuleID(t *testing.T) {
+	tests := []struct {
+		name       string
+		asset      Asset
+		path       string
+		wantName   string
+		wantSystem string
+		wantErr    string
+	}{
+		{name: "conventional directory", path: "modules/terraform-aws-vpc", wantName: "vpc", wantSystem: "aws"},
+		{name: "conventional tarball", path: "dist/terraform-google-network-peering.tgz", wantName: "network-peering", wantSystem: "google"},
+		{name: "explicit options", asset: Asset{ModuleName: "cluster", ModuleSystem: "azurerm"}, path: "infra/cluster", wantName: "cluster", wantSystem: "azurerm"},
+		{name: "option overrides directory", asset: Asset{ModuleName: "network"}, path: "terraform-aws-vpc", wantName: "network", wantSystem: "aws"},
+		{name: "unconventional directory", path: "infra/cluster", wantErr: "requires module_name and module_system"},
+		{name: "invalid system", asset: Asset{ModuleName: "vpc", ModuleSystem: "AWS"}, path: "vpc", wantErr: "invalid terraform module system"},
+		{name: "invalid name", asset: Asset{ModuleName: "my_vpc", ModuleSystem: "aws"}, path: "vpc", wantErr: "invalid terraform module name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, system, err := terraformModuleID(tt.asset, tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if name != tt.wantName || system != tt.wantSystem {
+				t.Errorf("got %s/%s, want %s/%s", name, system, tt.wantName, tt.wantSystem)
+			}
+		})
+	}
+}
+
+func TestPublishTerraformModule(t *testing.T) {
+	// Note: Not using t.Parallel() because os.Chdir affects global state
+
+	tmpDir := t.TempDir()
+	chdirForTest(t, tmpDir)
+	if err := os.MkdirAll(filepath.Join("modules", "terraform-aws-vpc", "examples"), 0755); err != nil {
+		t.Fatalf("failed to create module: %v", err)
+	}
+	_ = os.WriteFile(filepath.Join("modules", "terraform-aws-vpc", "main.tf"), []byte(`resource "aws_vpc" "this" {}`), 0644)
+	_ = os.WriteFile(filepath.Join("modules", "terraform-aws-vpc", "examples", "basic.tf"), []byte(`module "vpc" {}`), 0644)
+
+	client, requests := recordingServer(t)
+	p := &GitLabPlugin{}
+	releaseCtx := plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"}
+
+	published, err := p.uploadReleaseAsset(context.Background(), client, &Config{}, "Infra/modules", releaseCtx, Asset{Path: "modules/terraform-aws-vpc", Registry: "terraform"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reqs := requests()
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 upload, got %d", len(reqs))
+	}
+	wantPath := "/api/v4/projects/Infra%2Fmodules/packages/terraform/modules/vpc/aws/1%2E2%2E0/file"
+	if reqs[0].method != http.MethodPut || reqs[0].path != wantPath {
+		t.Errorf("unexpected request %s %s", reqs[0].method, reqs[0].path)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(reqs[0].body))
+	if err != nil {
+		t.Fatalf("expected gzip body: %v", err)
+	}
+	var names []string
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read module tarball: %v", err)
+		}
+		names = append(names, hdr.Name)
+	}
+	wantNames := []string{"examples/", "examples/basic.tf", "main.tf"}
+	if len(names) != len(wantNames) {
+		t.Fatalf("expected module files at archive root %v, got %v", wantNames, names)
+	}
+	for i := range wantNames {
+		if names[i] != wantNames[i] {
+			t.Errorf("entry %d: expected %q, got %q", i, wantNames[i], names[i])
+		}
+	}
+
+	if published.artifacts[0].Type != "terraform_module" || published.artifacts[0].Name != "vpc-aws-1.2.0.tgz" {
+		t.Errorf("unexpected artifact %+v", published.artifacts[0])
+	}
+	if published.link == nil || published.link.Name != "vpc/aws 1.2.0 (Terraform)" {
+		t.Fatalf("unexpected link %+v", published.link)
+	}
+	if !contains(published.link.URL, "/api/v4/packages/terraform/modules/v1/Infra/vpc/aws/1%2E2%2E0/file") {
+		t.Errorf("unexpected link URL %q", published.link.URL)
+	}
+
+	if _, err := p.uploadReleaseAsset(context.Background(), client, &Config{}, "infra/modules", plugin.ReleaseContext{Version: "nightly"}, Asset{Path: "modules/terraform-aws-vpc", Registry: "terraform"}); err == nil || !contains(err.Error(), "semantic") {
+		t.Errorf("expected semantic version error, got %v", err)
+	}
+}
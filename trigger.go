@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// TriggerPipeline describes a downstream pipeline to start after a release.
+type TriggerPipeline struct {
+	// Project is the project to run the pipeline in (default: the release project).
+	Project string `json:"project,omitempty"`
+	// Ref is the branch or tag to run the pipeline for.
+	Ref string `json:"ref"`
+	// Variables are passed to the pipeline; values may use release placeholders.
+	Variables map[string]string `json:"variables,omitempty"`
+	// TokenEnv names an environment variable holding a pipeline trigger token.
+	// Without it, the pipeline is created with the GitLab token.
+	TokenEnv string `json:"token_env,omitempty"`
+	// Wait polls the pipeline until it finishes.
+	Wait bool `json:"wait,omitempty"`
+	// Timeout bounds the wait (default: 30m).
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// defaultTriggerTimeout bounds how long a triggered pipeline is waited for.
+const defaultTriggerTimeout = 30 * time.Minute
+
+// triggeredPipeline is the result of a trigger, reported in Outputs.
+type triggeredPipeline struct {
+	Project    string `json:"project"`
+	Ref        string `json:"ref"`
+	PipelineID int64  `json:"pipeline_id,omitempty"`
+	WebURL     string `json:"web_url,omitempty"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+}
+
+// startPipeline runs the trigger and returns the created pipeline.
+func startPipeline(ctx context.Context, client *gitlab.Client, project string, trigger TriggerPipeline, variables map[string]string) (*gitlab.Pipeline, error) {
+	if trigger.TokenEnv != "" {
+		token := os.Getenv(trigger.TokenEnv)
+		if token == "" {
+			return nil, fmt.Errorf("trigger token variable %s is not set", trigger.TokenEnv)
+		}
+		pipeline, _, err := client.PipelineTriggers.RunPipelineTrigger(project, &gitlab.RunPipelineTriggerOptions{
+			Ref:       gitlab.Ptr(trigger.Ref),
+			Token:     gitlab.Ptr(token),
+			Variables: variables,
+		}, gitlab.WithContext(ctx))
+		return pipeline, err
+	}
+
+	opts := &gitlab.CreatePipelineOptions{Ref: gitlab.Ptr(trigger.Ref)}
+	if len(variables) > 0 {
+		keys := make([]string, 0, len(variables))
+		for key := range variables {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		vars := make([]*gitlab.PipelineVariableOptions, len(keys))
+		for i, key := range keys {
+			vars[i] = &gitlab.PipelineVariableOptions{Key: gitlab.Ptr(key), Value: gitlab.Ptr(variables[key])}
+		}
+		opts.Variables = &vars
+	}
+	pipeline, _, err := client.Pipelines.CreatePipeline(project, opts, gitlab.WithContext(ctx))
+	return pipeline, err
+}
+
+// waitForPipeline polls a pipeline until it leaves the pending states or the
+// timeout expires, returning its last known status.
+func waitForPipeline(ctx context.Context, client *gitlab.Client, project string, pipeline *gitlab.Pipeline, timeout, interval time.Duration) (string, error) {
+	deadline := time.Now().Add(timeout)
+	status := pipeline.Status
+	for pendingPipelineStatuses[status] {
+		if !time.Now().Add(interval).Before(deadline) {
+			return status, fmt.Errorf("pipeline %d still %s after %s", pipeline.ID, status, timeout)
+		}
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		current, _, err := client.Pipelines.GetPipeline(project, pipeline.ID, gitlab.WithContext(ctx))
+		if err != nil {
+			return status, fmt.Errorf("failed to get pipeline %d: %w", pipeline.ID, err)
+		}
+		status = current.Status
+	}
+	return status, nil
+}
+
+// triggerPipelines starts the configured downstream pipelines. Every trigger
+// is attempted; the run fails if any trigger fails or a waited-for pipeline
+// does not succeed.
+func (p *GitLabPlugin) triggerPipelines(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool) (*plugin.ExecuteResponse, error) {
+	client, err := p.getClient(cfg)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to create GitLab client: %v", err),
+		}, nil
+	}
+
+	interval, err := time.ParseDuration(cfg.PipelinePollInterval)
+	if err != nil || interval <= 0 {
+		interval = defaultPipelinePollInterval
+	}
+
+	results := make([]triggeredPipeline, 0, len(cfg.TriggerPipelines))
+	failed := 0
+	for _, trigger := range cfg.TriggerPipelines {
+		project := trigger.Project
+		if project == "" {
+			project = resolveProjectID(cfg, releaseCtx)
+		}
+		trigger.Ref = renderTemplate(trigger.Ref, releaseCtx)
+		variables := make(map[string]string, len(trigger.Variables))
+		for key, value := range trigger.Variables {
+			variables[key] = renderTemplate(value, releaseCtx)
+		}
+
+		result := triggeredPipeline{Project: project, Ref: trigger.Ref}
+		if dryRun {
+			result.Status = "skipped"
+			results = append(results, result)
+			continue
+		}
+
+		pipeline, err := startPipeline(ctx, client, project, trigger, variables)
+		if err != nil {
+			result.Status = "error"
+			result.Error = err.Error()
+			results = append(results, result)
+			failed++
+			continue
+		}
+		result.PipelineID = pipeline.ID
+		result.WebURL = pipeline.WebURL
+		result.Status = pipeline.Status
+
+		if trigger.Wait {
+			timeout, err := time.ParseDuration(trigger.Timeout)
+			if err != nil || timeout <= 0 {
+				timeout = defaultTriggerTimeout
+			}
+			result.Status, err = waitForPipeline(ctx, client, project, pipeline, timeout, interval)
+			if err != nil {
+				result.Error = err.Error()
+			}
+			if result.Status != "success" {
+				failed++
+			}
+		}
+		results = append(results, result)
+	}
+
+	outputs := map[string]any{"triggered_pipelines": results}
+	if dryRun {
+		return &plugin.ExecuteResponse{
+			Success: true,
+			Message: fmt.Sprintf("Would trigger %d downstream pipelines", len(results)),
+			Outputs: outputs,
+		}, nil
+	}
+	if failed > 0 {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("%d of %d downstream pipelines failed", failed, len(results)),
+			Outputs: outputs,
+		}, nil
+	}
+	return &plugin.ExecuteResponse{
+		Success: true,
+		Message: fmt.Sprintf("Triggered %d downstream pipelines", len(results)),
+		Outputs: outputs,
+	}, nil
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// triggerRequest records a pipeline creation or trigger request.
+type triggerRequest struct {
+	path string
+	body map[string]any
+}
+
+// triggerServer creates pipelines with ID 500 and reports the given statuses
+// for it in order, repeating the last one.
+func triggerServer(t *testing.T, statuses ...string) (string, func() []triggerRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []triggerRequest
+	polls := 0
+
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodPost && (contains(r.URL.Path, "/pipeline") || contains(r.URL.Path, "/trigger/pipeline")):
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			requests = append(requests, triggerRequest{path: r.URL.EscapedPath(), body: body})
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(gitlab.Pipeline{ID: 500, Status: "created", WebURL: "https://gitlab.example.com/docs/site/-/pipelines/500"})
+		case r.Method == http.MethodGet && contains(r.URL.Path, "/pipelines/500"):
+			status := statuses[min(polls, len(statuses)-1)]
+			polls++
+			_ = json.NewEncoder(w).Encode(gitlab.Pipeline{ID: 500, Status: status})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	return server.URL, func() []triggerRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]triggerRequest(nil), requests...)
+	}
+}
+
+func TestTriggerPipelines(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{Version: "1.4.0", TagName: "v1.4.0", RepositoryOwner: "group", RepositoryName: "project"}
+
+	t.Run("creates pipelines with templated variables", func(t *testing.T) {
+		serverURL, requests := triggerServer(t, "success")
+		cfg := &Config{
+			Token:   "glpat-test",
+			BaseURL: serverURL,
+			TriggerPipelines: []TriggerPipeline{
+				{Project: "docs/site", Ref: "main", Variables: map[string]string{"RELEASE_VERSION": "{version}", "RELEASE_TAG": "{tag_name}"}},
+				{Ref: "release-{version}"},
+			},
+		}
+
+		p := &GitLabPlugin{}
+		resp, err := p.triggerPipelines(context.Background(), cfg, releaseCtx, false)
+		if err != nil {
+			t.Fatalf("triggerPipelines returned error: %v", err)
+		}
+		if !resp.Success || resp.Message != "Triggered 2 downstream pipelines" {
+			t.Fatalf("unexpected response %+v", resp)
+		}
+
+		reqs := requests()
+		if len(reqs) != 2 {
+			t.Fatalf("expected 2 pipelines, got %d", len(reqs))
+		}
+		if reqs[0].path != "/api/v4/projects/docs%2Fsite/pipeline" || reqs[0].body["ref"] != "main" {
+			t.Errorf("unexpected first request %+v", reqs[0])
+		}
+		vars, _ := reqs[0].body["variables"].([]any)
+		if len(vars) != 2 || vars[0].(map[string]any)["key"] != "RELEASE_TAG" || vars[0].(map[string]any)["value"] != "v1.4.0" {
+			t.Errorf("unexpected variables %v", reqs[0].body["variables"])
+		}
+		if reqs[1].path != "/api/v4/projects/group%2Fproject/pipeline" || reqs[1].body["ref"] != "release-1.4.0" {
+			t.Errorf("expected release project and templated ref, got %+v", reqs[1])
+		}
+
+		results := resp.Outputs["triggered_pipelines"].([]triggeredPipeline)
+		if results[0].PipelineID != 500 || results[0].Status != "created" || results[0].Project != "docs/site" {
+			t.Errorf("unexpected results %+v", results)
+		}
+	})
+
+	t.Run("uses trigger token and waits", func(t *testing.T) {
+		serverURL, requests := triggerServer(t, "running", "success")
+		t.Setenv("HOMEBREW_TRIGGER_TOKEN", "trigger-secret")
+		cfg := &Config{
+			Token:                "glpat-test",
+			BaseURL:              serverURL,
+			PipelinePollInterval: "10ms",
+			TriggerPipelines: []TriggerPipeline{
+				{Project: "acme/homebrew-tap", Ref: "main", TokenEnv: "HOMEBREW_TRIGGER_TOKEN", Wait: true, Timeout: "5s", Variables: map[string]string{"VERSION": "{version}"}},
+			},
+		}
+
+		p := &GitLabPlugin{}
+		resp, err := p.triggerPipelines(context.Background(), cfg, releaseCtx, false)
+		if err != nil {
+			t.Fatalf("triggerPipelines returned error: %v", err)
+		}
+		if !resp.Success {
+			t.Fatalf("expected success, got %+v", resp)
+		}
+
+		reqs := requests()
+		if len(reqs) != 1 || reqs[0].path != "/api/v4/projects/acme%2Fhomebrew-tap/trigger/pipeline" {
+			t.Fatalf("unexpected requests %+v", reqs)
+		}
+		if reqs[0].body["token"] != "trigger-secret" || reqs[0].body["variables"].(map[string]any)["VERSION"] != "1.4.0" {
+			t.Errorf("unexpected trigger body %v", reqs[0].body)
+		}
+		if results := resp.Outputs["triggered_pipelines"].([]triggeredPipeline); results[0].Status != "success" {
+			t.Errorf("expected waited status success, got %+v", results)
+		}
+	})
+
+	t.Run("reports failed and missing-token triggers", func(t *testing.T) {
+		serverURL, _ := triggerServer(t, "failed")
+		cfg := &Config{
+			Token:                "glpat-test",
+			BaseURL:              serverURL,
+			PipelinePollInterval: "10ms",
+			TriggerPipelines: []TriggerPipeline{
+				{Project: "docs/site", Ref: "main", Wait: true},
+				{Project: "acme/homebrew-tap", Ref: "main", TokenEnv: "MISSING_TRIGGER_TOKEN"},
+			},
+		}
+
+		p := &GitLabPlugin{}
+		resp, err := p.triggerPipelines(context.Background(), cfg, releaseCtx, false)
+		if err != nil {
+			t.Fatalf("triggerPipelines returned error: %v", err)
+		}
+		if resp.Success || resp.Error != "2 of 2 downstream pipelines failed" {
+			t.Fatalf("unexpected response %+v", resp)
+		}
+		results := resp.Outputs["triggered_pipelines"].([]triggeredPipeline)
+		if results[0].Status != "failed" || results[1].Status != "error" || !contains(results[1].Error, "MISSING_TRIGGER_TOKEN") {
+			t.Errorf("unexpected results %+v", results)
+		}
+	})
+}
+
+func TestExecuteOnSuccessCombinesActions(t *testing.T) {
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case contains(r.URL.Path, "/environments") && r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`[{"id": 9, "name": "production"}]`))
+		case contains(r.URL.Path, "/deployments"):
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message": "403 Forbidden"}`))
+		case contains(r.URL.Path, "/pipeline"):
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 500, "status": "created"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	p := &GitLabPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookOnSuccess,
+		Config: map[string]any{
+			"token":             "glpat-test",
+			"base_url":          server.URL,
+			"project_id":        "group/project",
+			"environment":       "production",
+			"trigger_pipelines": []any{map[string]any{"project": "docs/site", "ref": "main"}},
+		},
+		Context: plugin.ReleaseContext{TagName: "v1.0.0", CommitSHA: "abc123"},
+	})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+
+	// The failed deployment does not prevent the trigger
+	if resp.Success || !contains(resp.Error, "failed to create deployment") {
+		t.Errorf("expected deployment failure, got %+v", resp)
+	}
+	if resp.Message != "Triggered 1 downstream pipelines" {
+		t.Errorf("expected trigger message, got %q", resp.Message)
+	}
+	if _, ok := resp.Outputs["triggered_pipelines"]; !ok {
+		t.Errorf("expected trigger outputs, got %v", resp.Outputs)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// getExistingRelease returns the release for tagName, or nil if there is none.
+func getExistingRelease(ctx context.Context, client *gitlab.Client, projectID, tagName string) (*gitlab.Release, error) {
+	release, _, err := client.Releases.GetRelease(projectID, tagName, gitlab.WithContext(ctx))
+	if errors.Is(err, gitlab.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release %s: %w", tagName, err)
+	}
+	return release, nil
+}
+
+// updateRelease brings an existing release to the state of a create release
+// request: name, description, milestones and released_at are updated and its
+// links are synced with it. Links that are not in the request are deleted if
+// prune returns true for them, then changed links are updated and missing
+// ones created. It returns the updated release and the changes that were made.
+func updateRelease(ctx context.Context, client *gitlab.Client, projectID string, existing *gitlab.Release, opts *gitlab.CreateReleaseOptions, prune func(releaseLink) bool) (*gitlab.Release, *releaseDiff, error) {
+	tagName := *opts.TagName
+	diff := diffRelease(existing, opts, prune)
+
+	// Milestones are desired state too, so none configured removes them
+	milestones := []string{}
+	if opts.Milestones != nil {
+		milestones = *opts.Milestones
+	}
+	release, _, err := client.Releases.UpdateRelease(projectID, tagName, &gitlab.UpdateReleaseOptions{
+		Name:        opts.Name,
+		Description: opts.Description,
+		Milestones:  &milestones,
+		ReleasedAt:  opts.ReleasedAt,
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to update release: %w", err)
+	}
+
+	// Names, URLs and filepaths are unique within a release, so pruned links
+	// are deleted before others take over their values
+	for _, link := range diff.LinksRemoved {
+		if _, _, err := client.ReleaseLinks.DeleteReleaseLink(projectID, tagName, link.ID, gitlab.WithContext(ctx)); err != nil {
+			return nil, nil, fmt.Errorf("failed to delete release link %s: %w", link.Name, err)
+		}
+	}
+	for _, change := range diff.LinksChanged {
+		if err := updateLink(ctx, client, projectID, tagName, change.From.ID, change.To); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, link := range diff.LinksAdded {
+		if _, _, err := client.ReleaseLinks.CreateReleaseLink(projectID, tagName, createLinkOptions(link), gitlab.WithContext(ctx)); err != nil {
+			return nil, nil, fmt.Errorf("failed to create release link %s: %w", link.Name, err)
+		}
+	}
+	return release, diff, nil
+}
+
+// prunableLinks returns, when prune_links is set, the function that decides
+// whether a published link that is not configured is deleted. Links to files
+// the plugin publishes itself are kept: package registry files of a project,
+// such as registry assets, the SBOM and provenance, Terraform modules and
+// container images.
+func prunableLinks(client *gitlab.Client, cfg *Config) func(releaseLink) bool {
+	if !cfg.PruneLinks {
+		return nil
+	}
+	apiURL := client.BaseURL().String()
+	var registryHost string
+	if registryURL, err := containerRegistryURL(cfg); err == nil {
+		registryHost = registryURL.Host
+	}
+
+	return func(link releaseLink) bool {
+		switch u, err := url.Parse(link.URL); {
+		case strings.HasPrefix(link.URL, apiURL+"projects/") && strings.Contains(link.URL, "/packages/"):
+			return false
+		case strings.HasPrefix(link.URL, apiURL+"packages/"):
+			return false
+		case err == nil && registryHost != "" && u.Host == registryHost:
+			return false
+		}
+		return true
+	}
+}
+
+// updateLink changes the URL, type and filepath of a link.
+func updateLink(ctx context.Context, client *gitlab.Client, projectID, tagName string, id int64, link AssetLink) error {
+	opts := &gitlab.UpdateReleaseLinkOptions{
+		Name:            gitlab.Ptr(link.Name),
+		URL:             gitlab.Ptr(link.URL),
+		DirectAssetPath: gitlab.Ptr(link.FilePath),
+		LinkType:        gitlab.Ptr(gitlab.LinkTypeValue(link.LinkType)),
+	}
+	if link.LinkType == "" {
+		opts.LinkType = gitlab.Ptr(gitlab.OtherLinkType)
+	}
+	if _, _, err := client.ReleaseLinks.UpdateReleaseLink(projectID, tagName, id, opts, gitlab.WithContext(ctx)); err != nil {
+		return fmt.Errorf("failed to update release link %s: %w", link.Name, err)
+	}
+	return nil
+}
+
+// createLinkOptions returns the request that creates link.
+func createLinkOptions(link AssetLink) *gitlab.CreateReleaseLinkOptions {
+	opts := &gitlab.CreateReleaseLinkOptions{
+		Name:     gitlab.Ptr(link.Name),
+		URL:      gitlab.Ptr(link.URL),
+		LinkType: gitlab.Ptr(gitlab.LinkTypeValue(link.LinkType)),
+	}
+	if link.LinkType == "" {
+		opts.LinkType = gitlab.Ptr(gitlab.OtherLinkType)
+	}
+	if link.FilePath != "" {
+		opts.DirectAssetPath = gitlab.Ptr(link.FilePath)
+	}
+	return opts
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestExecuteUpdateExisting(t *testing.T) {
+	t.Parallel()
+
+	published := `{
+		"tag_name": "v1.4.0",
+		"name": "Release 1.4.0",
+		"description": "Old notes",
+		"milestones": [{"title": "1.4"}],
+		"assets": {"links": [
+			{"id": 1, "name": "Docs", "url": "https://docs.example.com", "direct_asset_url": "https://docs.example.com", "link_type": "runbook"},
+			{"id": 2, "name": "Binary", "url": "https://cdn.example.com/app", "direct_asset_url": "https://gitlab.example.com/acme/app/-/releases/v1.4.0/downloads/bin/app", "link_type": "package"},
+			{"id": 3, "name": "Stale", "url": "https://old.example.com", "direct_asset_url": "https://old.example.com", "link_type": "other"}
+		]}
+	}`
+
+	tests := []struct {
+		name        string
+		release     string
+		prune       bool
+		wantCalls   []string
+		wantMessage string
+		wantUpdated bool
+	}{
+		{
+			name:    "release is created when missing",
+			release: "",
+			wantCalls: []string{
+				"POST /releases",
+			},
+			wantMessage: "Created GitLab release",
+		},
+		{
+			name:    "links are synced",
+			release: published,
+			wantCalls: []string{
+				"PUT /releases/v1.4.0",
+				"PUT /releases/v1.4.0/assets/links/1",
+				"POST /releases/v1.4.0/assets/links",
+			},
+			wantMessage: "(links: +1 ~1 -0)",
+			wantUpdated: true,
+		},
+		{
+			name:    "stale links are pruned",
+			release: published,
+			prune:   true,
+			wantCalls: []string{
+				"PUT /releases/v1.4.0",
+				"DELETE /releases/v1.4.0/assets/links/3",
+				"PUT /releases/v1.4.0/assets/links/1",
+				"POST /releases/v1.4.0/assets/links",
+			},
+			wantMessage: "(links: +1 ~1 -1)",
+			wantUpdated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			var calls []string
+			var update map[string]any
+			server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				path := r.URL.Path[len("/api/v4/projects/acme/app"):]
+				if r.Method != http.MethodGet {
+					calls = append(calls, r.Method+" "+path)
+				}
+				switch {
+				case r.Method == http.MethodGet && path == "/releases/v1.4.0":
+					if tt.release == "" {
+						http.NotFound(w, r)
+						return
+					}
+					_, _ = w.Write([]byte(tt.release))
+				case r.Method == http.MethodPut && path == "/releases/v1.4.0":
+					_ = json.NewDecoder(r.Body).Decode(&update)
+					_, _ = w.Write([]byte(`{"tag_name": "v1.4.0", "name": "Acme 1.4.0"}`))
+				case r.Method == http.MethodPost && path == "/releases":
+					w.WriteHeader(http.StatusCreated)
+					_, _ = w.Write([]byte(`{"tag_name": "v1.4.0", "name": "Acme 1.4.0"}`))
+				case r.Method == http.MethodPost && contains(path, "/assets/links"):
+					w.WriteHeader(http.StatusCreated)
+					_, _ = w.Write([]byte(`{"id": 4}`))
+				case r.Method == http.MethodPut || r.Method == http.MethodDelete:
+					_, _ = w.Write([]byte(`{"id": 1}`))
+				case r.Method == http.MethodGet && contains(path, "/assets/links"):
+					_, _ = w.Write([]byte(`[]`))
+				default:
+					http.NotFound(w, r)
+				}
+			})
+
+			p := &GitLabPlugin{}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook: plugin.HookPostPublish,
+				Config: map[string]any{
+					"token":           "glpat-test",
+					"base_url":        server.URL,
+					"project_id":      "acme/app",
+					"name":            "Acme {version}",
+					"update_existing": true,
+					"prune_links":     tt.prune,
+					"asset_links": []any{
+						map[string]any{"name": "Docs", "url": "https://docs.example.com/v1", "link_type": "runbook"},
+						map[string]any{"name": "Binary", "url": "https://cdn.example.com/app", "filepath": "/bin/app", "link_type": "package"},
+						map[string]any{"name": "Checksums", "url": "https://cdn.example.com/sha256sums"},
+					},
+				},
+				Context: plugin.ReleaseContext{Version: "1.4.0", TagName: "v1.4.0", ReleaseNotes: "Notes"},
+			})
+			if err != nil {
+				t.Fatalf("Execute returned error: %v", err)
+			}
+			if !resp.Success {
+				t.Fatalf("expected success, got %+v", resp)
+			}
+			if !contains(resp.Message, tt.wantMessage) {
+				t.Errorf("expected message containing %q, got %q", tt.wantMessage, resp.Message)
+			}
+			if resp.Outputs["updated"] != tt.wantUpdated {
+				t.Errorf("expected updated %v, got %v", tt.wantUpdated, resp.Outputs["updated"])
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(calls) != len(tt.wantCalls) {
+				t.Fatalf("expected calls %v, got %v", tt.wantCalls, calls)
+			}
+			for i, want := range tt.wantCalls {
+				if calls[i] != want {
+					t.Errorf("call %d: expected %q, got %q", i, want, calls[i])
+				}
+			}
+			if tt.wantUpdated {
+				milestones, ok := update["milestones"].([]any)
+				if update["name"] != "Acme 1.4.0" || update["description"] != "Notes" || !ok || len(milestones) != 0 {
+					t.Errorf("unexpected release update %v", update)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteUpdateRenamedLink(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var calls []string
+	deleted := false
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		path := r.URL.Path[len("/api/v4/projects/acme/app"):]
+		if r.Method != http.MethodGet {
+			calls = append(calls, r.Method+" "+path)
+		}
+		switch {
+		case r.Method == http.MethodGet && path == "/releases/v1.4.0":
+			_, _ = w.Write([]byte(`{"tag_name": "v1.4.0", "name": "Release 1.4.0", "assets": {"links": [
+				{"id": 1, "name": "Docs", "url": "https://docs.example.com", "direct_asset_url": "https://gitlab.example.com/acme/app/-/releases/v1.4.0/downloads/docs", "link_type": "runbook"}
+			]}}`))
+		case r.Method == http.MethodPut && path == "/releases/v1.4.0":
+			_, _ = w.Write([]byte(`{"tag_name": "v1.4.0", "name": "Release 1.4.0"}`))
+		case r.Method == http.MethodDelete && path == "/releases/v1.4.0/assets/links/1":
+			deleted = true
+			_, _ = w.Write([]byte(`{"id": 1}`))
+		case r.Method == http.MethodPost && path == "/releases/v1.4.0/assets/links":
+			// GitLab rejects a URL or filepath that another link still uses
+			if !deleted {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"message": {"url": ["has already been taken"]}}`))
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 2}`))
+		case r.Method == http.MethodGet && contains(path, "/assets/links"):
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	p := &GitLabPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"token":           "glpat-test",
+			"base_url":        server.URL,
+			"project_id":      "acme/app",
+			"update_existing": true,
+			"prune_links":     true,
+			"asset_links": []any{
+				map[string]any{"name": "Documentation", "url": "https://docs.example.com", "filepath": "/docs", "link_type": "runbook"},
+			},
+		},
+		Context: plugin.ReleaseContext{Version: "1.4.0", TagName: "v1.4.0", ReleaseNotes: "Notes"},
+	})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if !resp.Success || !contains(resp.Message, "(links: +1 ~0 -1)") {
+		t.Fatalf("expected the link to be renamed, got %+v", resp)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"PUT /releases/v1.4.0", "DELETE /releases/v1.4.0/assets/links/1", "POST /releases/v1.4.0/assets/links"}
+	if len(calls) != len(want) {
+		t.Fatalf("expected calls %v, got %v", want, calls)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("call %d: expected %q, got %q", i, want[i], calls[i])
+		}
+	}
+}
+
+func TestCreateAssetLinkUpdatesExistingLink(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var updated map[string]any
+	server := setupMockGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message": {"name": ["has already been taken"]}}`))
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`[{"id": 9, "name": "SBOM (CycloneDX)", "url": "https://old.example.com/sbom.json"}]`))
+		case r.Method == http.MethodPut && contains(r.URL.Path, "/assets/links/9"):
+			_ = json.NewDecoder(r.Body).Decode(&updated)
+			_, _ = w.Write([]byte(`{"id": 9}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	client, err := gitlab.NewClient("glpat-test", gitlab.WithBaseURL(server.URL+"/api/v4/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &GitLabPlugin{}
+	link := AssetLink{Name: "SBOM (CycloneDX)", URL: "https://gitlab.example.com/sbom.cdx.json", FilePath: "/sbom.cdx.json"}
+	if err := p.createAssetLink(context.Background(), client, "acme/app", "v1.4.0", link); err != nil {
+		t.Fatalf("createAssetLink returned error: %v", err)
+	}
+	mu.Lock()
+	if updated["url"] != link.URL || updated["direct_asset_path"] != "/sbom.cdx.json" || updated["link_type"] != "other" {
+		t.Errorf("unexpected link update %v", updated)
+	}
+	mu.Unlock()
+
+	link.Name = "Provenance"
+	if err := p.createAssetLink(context.Background(), client, "acme/app", "v1.4.0", link); err == nil || !contains(err.Error(), "failed to create release link Provenance") {
+		t.Errorf("expected the creation error for an unknown link, got %v", err)
+	}
+}
+
+func TestPrunableLinks(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, "https://gitlab.example.com")
+	cfg := &Config{BaseURL: "https://gitlab.example.com", PruneLinks: true}
+	prune := prunableLinks(client, cfg)
+
+	tests := []struct {
+		name string
+		url  string
+		want bool
+	}{
+		{name: "configured link", url: "https://docs.example.com", want: true},
+		{name: "SBOM", url: "https://gitlab.example.com/api/v4/projects/acme%2Fapp/packages/generic/release-assets/v1%2E4%2E0/sbom%2Ecdx%2Ejson", want: false},
+		{name: "registry package", url: "https://gitlab.example.com/api/v4/projects/acme%2Fapp/packages/rpm/12/app-1.4.0-1.x86_64.rpm", want: false},
+		{name: "terraform module", url: "https://gitlab.example.com/api/v4/packages/terraform/modules/v1/acme/vpc/aws/1.4.0/file", want: false},
+		{name: "container image", url: "https://registry.gitlab.example.com/acme/app:1.4.0", want: false},
+		{name: "release page of the project", url: "https://gitlab.example.com/acme/app/-/releases/v1.3.0", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := prune(releaseLink{Name: tt.name, URL: tt.url}); got != tt.want {
+				t.Errorf("prune(%s) = %v, want %v", tt.url, got, tt.want)
+			}
+		})
+	}
+
+	if prunableLinks(client, &Config{}) != nil {
+		t.Errorf("expected no pruning without prune_links")
+	}
+}